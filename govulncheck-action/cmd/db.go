@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/vulndb"
+	"github.com/spf13/cobra"
+)
+
+func NewDBCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "db",
+		Short: "Manage the vulnerability database",
+		Args:  exactArgs(0),
+	}
+	cmd.AddCommand(NewDBSnapshotCmd())
+	return cmd
+}
+
+func NewDBSnapshotCmd() *cobra.Command {
+	var dir, source string
+	var parallelism int
+	var debug bool
+	var cmd = &cobra.Command{
+		Use:          "snapshot",
+		Short:        "Download the vulnerability database into a local directory, to use with the '--db' flag of 'vuln-check'",
+		SilenceUsage: true,
+		Args:         exactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if parallelism < 1 {
+				return failure.New(failure.KindConfig, fmt.Errorf("invalid parallelism: %d (must be at least 1)", parallelism))
+			}
+			logger := newLogger(cmd.OutOrStdout(), debug)
+			if err := vulndb.Snapshot(cmd.Context(), logger, http.DefaultClient, source, dir, parallelism); err != nil {
+				if failure.KindOf(err) == failure.KindTimeout {
+					return err
+				}
+				return failure.New(failure.KindDB, err)
+			}
+			logger.Info("vulnerability database downloaded", "dir", dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "path to the directory in which the vulnerability database is downloaded")
+	if err := cmd.MarkFlagRequired("dir"); err != nil {
+		log.Fatalf("failed to mark flag required: %v", err)
+	}
+	cmd.Flags().StringVar(&source, "source", vulndb.DefaultSource, "URL of the vulnerability database to download")
+	cmd.Flags().IntVar(&parallelism, "parallelism", 10, "maximum number of entries downloaded concurrently")
+	cmd.Flags().BoolVar(&debug, "debug", false, "debug mode")
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// formatText prints the differences in a human-readable format
+	formatText = "text"
+	// formatJSON prints the differences in JSON
+	formatJSON = "json"
+)
+
+func NewDiffCmd() *cobra.Command {
+	var format string
+	var cmd = &cobra.Command{
+		Use:          "diff <old.json> <new.json>",
+		Short:        "Compare the JSON outputs of two scans (written with the '--json-output' flag of 'vuln-check'), and report the added, removed and changed vulnerabilities",
+		SilenceUsage: true,
+		Args:         exactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != formatText && format != formatJSON {
+				return failure.New(failure.KindConfig, fmt.Errorf("invalid format: '%s' (must be '%s' or '%s')", format, formatText, formatJSON))
+			}
+			oldVulns, err := govulncheck.ReadResults(args[0])
+			if err != nil {
+				return failure.New(failure.KindConfig, err)
+			}
+			newVulns, err := govulncheck.ReadResults(args[1])
+			if err != nil {
+				return failure.New(failure.KindConfig, err)
+			}
+			diff := govulncheck.DiffVulnerabilities(oldVulns, newVulns)
+			if format == formatJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(diff)
+			}
+			govulncheck.PrintDiff(cmd.OutOrStdout(), diff)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", formatText, "output format: 'text' or 'json'")
+	return cmd
+}
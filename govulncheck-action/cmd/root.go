@@ -1,84 +1,422 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/cache"
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/toolchain"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/vulndb"
 	"github.com/spf13/cobra"
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The command is cancelled on SIGTERM or SIGINT, and exits with a code which depends on the kind of failure.
 func Execute() {
-	err := NewVulnCheckCmd().Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	err := NewVulnCheckCmd().ExecuteContext(ctx)
+	stop()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(failure.ExitCode(err))
 	}
 }
 
+const (
+	// reportInfo reports the vulnerabilities as informational
+	reportInfo = "info"
+	// reportFail reports the vulnerabilities as failing
+	reportFail = "fail"
+)
+
+// vulnCheckFlags contains the values of the flags of the `vuln-check` command
+type vulnCheckFlags struct {
+	configFile         string
+	path               string
+	mode               string
+	scanLevel          string
+	unreachable        string
+	testOnly           string
+	showTraces         string
+	severityData       string
+	minSeverity        string
+	baselineFile       string
+	jsonOutput         string
+	gotoolchain        string
+	db                 string
+	cacheDir           string
+	fromReport         string
+	binaries           []string
+	imageArchives      []string
+	platforms          []string
+	tags               []string
+	packages           []string
+	excludes           []string
+	failOn             []string
+	parallelism        int
+	unreviewedWarnDays int
+	timeout            time.Duration
+	includeTests       bool
+	strictReport       bool
+	debug              bool
+}
+
 func NewVulnCheckCmd() *cobra.Command {
-	var configFile, path string
-	var debug bool
+	f := &vulnCheckFlags{}
 	var cmd = &cobra.Command{
 		Use:          "vuln-check",
 		Short:        "Run govulncheck and exclude vulnerabilities listed in the '--ignored' YAML file",
 		SilenceUsage: true,
-		Args:         cobra.ExactArgs(0),
+		Args:         exactArgs(0),
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			// validate the required flags before cobra does, so that a missing flag is reported as a configuration error
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return failure.New(failure.KindConfig, err)
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			config, err := configuration.New(configFile)
+			config, err := configuration.New(f.configFile)
 			if err != nil {
-				return err
+				return failure.New(failure.KindConfig, err)
 			}
-			opts := &slog.HandlerOptions{
-				Level: slog.LevelInfo,
+			opts, err := validateOptions(f, config)
+			if err != nil {
+				return failure.New(failure.KindConfig, err)
 			}
-			if debug {
-				opts.Level = slog.LevelDebug
+			logger := newLogger(cmd.OutOrStdout(), f.debug)
+			if opts.Mode == govulncheck.ModeBinary && (len(config.Packages) > 0 || len(config.Exclude) > 0) {
+				logger.Debug("ignoring the packages and excludes of the config file in 'binary' mode", "packages", config.Packages, "exclude", config.Exclude)
 			}
-			handler := slog.NewTextHandler(cmd.OutOrStdout(), opts)
-			logger := slog.New(handler)
 			// check the current working directory
 			workingDir, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("failed to get working directory: %w", err)
 			}
 			logger.Debug("working directory", "path", workingDir)
-			// check that there is a `go.mod` file in the path
-			// (required by the underlying govulncheck command, but here we can collect insights of failures)
-			gomodCmd := exec.CommandContext(cmd.Context(), "go", "env", "GOMOD")
-			gomodCmd.Dir = path
-			output, err := gomodCmd.Output()
-			if err != nil {
-				return fmt.Errorf("failed to get `go.mod` file: %w", err)
+			if opts.DB != "" {
+				logger.Debug("vulnerability database", "url", opts.DB)
 			}
-			logger.Debug("`go.mod` file", "path", string(output))
-			vulns, outdatedVulns, err := govulncheck.Scan(cmd.Context(), logger, govulncheck.DefaultScan(cmd.OutOrStderr()), path, config)
+			ctx := cmd.Context()
+			if f.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, f.timeout)
+				defer cancel()
+			}
+			if opts.Mode == govulncheck.ModeSource && !opts.FromReport {
+				if opts.Env, err = prepareSource(ctx, logger, opts.Path, f.gotoolchain); err != nil {
+					return err
+				}
+			}
+			scan := govulncheck.DefaultScan(cmd.OutOrStderr())
 			switch {
-			case err != nil:
+			case f.fromReport != "":
+				scan = govulncheck.ReportScan(f.fromReport, cmd.InOrStdin())
+			case f.cacheDir != "":
+				scan = cache.New(f.cacheDir, http.DefaultClient).Scan(scan)
+			}
+			vulns, outdatedVulns, excludedPackages, err := govulncheck.Scan(ctx, logger, scan, opts, config)
+			govulncheck.PrintExcludedPackages(cmd.OutOrStdout(), excludedPackages)
+			if err != nil {
+				if len(vulns) > 0 {
+					// partial report when the scan timed out or was cancelled
+					govulncheck.PrintVulnerabilities(cmd.OutOrStdout(), vulns, f.showTraces)
+				}
 				return err
-			case len(vulns) > 0 || len(outdatedVulns) > 0:
-				govulncheck.PrintVulnerabilities(cmd.OutOrStdout(), vulns)
+			}
+			if f.jsonOutput != "" {
+				if err := govulncheck.WriteResults(f.jsonOutput, vulns, excludedPackages); err != nil {
+					return err
+				}
+			}
+			failingVulns := govulncheck.FailingVulnerabilities(vulns)
+			switch {
+			case len(failingVulns) > 0 || len(outdatedVulns) > 0:
+				govulncheck.PrintVulnerabilities(cmd.OutOrStdout(), vulns, f.showTraces)
+				govulncheck.PrintUpgrades(cmd.OutOrStdout(), govulncheck.GetUpgrades(failingVulns))
 				govulncheck.PrintOutdatedVulnerabilities(cmd.OutOrStdout(), outdatedVulns)
-				return fmt.Errorf("%d vulnerabilities found and %d outdated vulnerabilities found", len(vulns), len(outdatedVulns))
+				return failure.New(failure.KindVulnerabilities, fmt.Errorf("%d vulnerabilities found and %d outdated vulnerabilities found", len(failingVulns), len(outdatedVulns)))
+			case len(vulns) > 0:
+				govulncheck.PrintVulnerabilities(cmd.OutOrStdout(), vulns, f.showTraces)
+				logger.Info("only informational vulnerabilities found", "count", len(vulns))
+				return nil
 			default:
 				logger.Info("no vulnerabilities found")
 				return nil
 			}
 		},
 	}
-	cmd.Flags().StringVar(&configFile, "config", "", "path to the ignored vulnerabilities config file")
+	cmd.Flags().StringVar(&f.configFile, "config", "", "path to the ignored vulnerabilities config file")
 	if err := cmd.MarkFlagRequired("config"); err != nil {
 		log.Fatalf("failed to mark flag required: %v", err)
 	}
-	cmd.Flags().StringVar(&path, "path", ".", "path to the repository root directory to scan")
+	cmd.Flags().StringVar(&f.path, "path", ".", "path to the repository root directory to scan")
 	if err := cmd.MarkFlagRequired("path"); err != nil {
 		log.Fatalf("failed to mark flag required: %v", err)
 	}
-	cmd.Flags().BoolVar(&debug, "debug", false, "debug mode")
+	cmd.Flags().StringVar(&f.mode, "mode", govulncheck.ModeSource, "scan mode: 'source' to scan the Go modules in the path, or 'binary' to scan the binaries specified with '--binary'")
+	cmd.Flags().StringVar(&f.scanLevel, "scan-level", govulncheck.ScanLevelSymbol, "level of the analysis: 'module', 'package' or 'symbol'")
+	cmd.Flags().StringVar(&f.unreachable, "unreachable", reportInfo, "how to report the vulnerabilities found at a less precise level than the scan level (eg: imported but not called): 'info' or 'fail'")
+	cmd.Flags().BoolVar(&f.includeTests, "include-tests", false, "analyze the test files (only in 'source' mode)")
+	cmd.Flags().StringVar(&f.testOnly, "test-only", reportFail, "how to report the vulnerabilities which are only reachable from test code ('_test.go' files or 'test/e2e' packages): 'info' or 'fail'")
+	cmd.Flags().IntVar(&f.unreviewedWarnDays, "unreviewed-warn-days", 0, "number of days after their publication during which the vulnerabilities of unreviewed advisories are reported as informational instead of failing the scan (0 to always fail)")
+	cmd.Flags().StringVar(&f.showTraces, "show-traces", govulncheck.TracesCompact, "how to show the traces of the vulnerable symbols: 'full' for the call stacks from the entry points to the vulnerable symbols (with their receivers), 'compact' for the locations of the entry points, or 'none'")
+	cmd.Flags().StringVar(&f.severityData, "severity-data", "", "path to a JSON file with the CVSS severities of the vulnerabilities keyed by alias (eg: 'CVE-2025-22871'), used to show and sort the vulnerabilities by severity")
+	cmd.Flags().StringVar(&f.minSeverity, "min-severity", "", "minimum severity of the vulnerabilities which fail the scan ('low', 'medium', 'high', 'critical' or a CVSS score), the others being informational (requires '--severity-data')")
+	cmd.Flags().StringSliceVar(&f.failOn, "fail-on", []string{govulncheck.FailOnAll}, "selectors of the vulnerabilities which fail the scan, the others being informational: 'all', 'stdlib', 'third-party', 'fixable', 'unfixable' or 'reachable' (comma-separated and/or repeated, the vulnerabilities must match one of the selectors of each category, eg: 'third-party,fixable')")
+	cmd.Flags().StringVar(&f.baselineFile, "baseline", "", "path to the JSON output of a previous scan (eg: of the target branch), in which case only the vulnerabilities which are new or have new call sites fail the scan, the others being reported as informational")
+	cmd.Flags().StringVar(&f.jsonOutput, "json-output", "", "path to the file in which the results of the scan are written in JSON, including the fingerprints of the findings (eg: to be used as a baseline)")
+	cmd.Flags().StringSliceVar(&f.packages, "packages", nil, "patterns of the packages to scan in 'source' mode (comma-separated and/or repeated, default './...', overrides the 'packages' of the config file)")
+	cmd.Flags().StringSliceVar(&f.excludes, "exclude", nil, "globs of the directories (relative to the path) of the packages to exclude from the scan in 'source' mode, including their subdirectories (comma-separated and/or repeated, combined with the 'exclude' of the config file)")
+	cmd.Flags().StringArrayVar(&f.binaries, "binary", nil, "paths to the binaries to scan in 'binary' mode (comma-separated, newline-separated and/or repeated)")
+	cmd.Flags().StringArrayVar(&f.imageArchives, "image-archive", nil, "path to an image archive ('docker save' or OCI layout tarball, or OCI layout directory) whose Go binaries are scanned in 'binary' mode (can be repeated)")
+	cmd.Flags().StringSliceVar(&f.platforms, "platform", nil, "target platforms in which the source code is analyzed, in the '<os>/<arch>' or '<os>/<arch>/<variant>' format (comma-separated and/or repeated, eg: 'linux/amd64,linux/arm/v7')")
+	cmd.Flags().StringArrayVar(&f.tags, "tags", nil, "comma-separated list of build tags with which the source code is analyzed (can be repeated to analyze with multiple sets of build tags, an empty value meaning no build tags)")
+	cmd.Flags().StringVar(&f.gotoolchain, "gotoolchain", "", "Go toolchain with which the code is analyzed in 'source' mode (eg: 'go1.26.1' or 'local'), instead of the one specified in the 'go.work' or 'go.mod' file")
+	cmd.Flags().StringVar(&f.db, "db", "", "vulnerability database URL, or path to a local directory containing a snapshot of the database (default 'https://vuln.go.dev')")
+	cmd.Flags().StringVar(&f.cacheDir, "cache-dir", "", "path to the directory in which the govulncheck reports are cached, and reused as long as the dependencies, the source code (at the 'package' and 'symbol' levels), the Go version and the vulnerability database do not change (no cache if empty)")
+	cmd.Flags().StringVar(&f.fromReport, "from-report", "", "path to a govulncheck JSON report (or '-' for stdin) to evaluate against the config file, instead of running govulncheck")
+	cmd.Flags().BoolVar(&f.strictReport, "strict-report", false, "fail when a govulncheck report contains invalid findings (eg: without trace or OSV entry), instead of skipping them with a warning")
+	cmd.Flags().IntVar(&f.parallelism, "parallelism", 1, "maximum number of modules or binaries scanned concurrently")
+	cmd.Flags().DurationVar(&f.timeout, "timeout", 0, "maximum duration of the scan, after which the vulnerabilities found in the targets scanned so far are reported (no timeout if 0)")
+	cmd.Flags().BoolVar(&f.debug, "debug", false, "debug mode")
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return failure.New(failure.KindConfig, err)
+	})
+	cmd.AddCommand(NewDBCmd())
+	cmd.AddCommand(NewDiffCmd())
 	return cmd
 }
+
+// validateOptions validates the values of the flags, and returns the options of the scan.
+// The packages and excludes of the config file are merged with the ones of the flags in `source` mode.
+func validateOptions(f *vulnCheckFlags, config configuration.Configuration) (govulncheck.Options, error) {
+	if f.parallelism < 1 {
+		return govulncheck.Options{}, fmt.Errorf("invalid parallelism: %d (must be at least 1)", f.parallelism)
+	}
+	if f.timeout < 0 {
+		return govulncheck.Options{}, fmt.Errorf("invalid timeout: %s (must not be negative)", f.timeout)
+	}
+	// ignore empty values (eg: when the inputs of the action are not set)
+	f.binaries = splitList(f.binaries)
+	f.imageArchives = slices.DeleteFunc(f.imageArchives, isEmpty)
+	f.platforms = slices.DeleteFunc(f.platforms, isEmpty)
+	f.packages = slices.DeleteFunc(f.packages, isEmpty)
+	f.excludes = slices.DeleteFunc(f.excludes, isEmpty)
+	if err := validateMode(f); err != nil {
+		return govulncheck.Options{}, err
+	}
+	packages, excludes := f.packages, f.excludes
+	if f.mode == govulncheck.ModeSource && f.fromReport == "" {
+		// the packages specified with the flags take precedence over the ones in the config file,
+		// while the excludes are combined.
+		// In `binary` mode, the packages and excludes of the config file do not apply (and are ignored)
+		if len(packages) == 0 {
+			packages = config.Packages
+		}
+		excludes = slices.Concat(excludes, config.Exclude)
+	}
+	if err := govulncheck.ValidateExcludes(excludes); err != nil {
+		return govulncheck.Options{}, err
+	}
+	if err := validateReporting(f); err != nil {
+		return govulncheck.Options{}, err
+	}
+	opts := govulncheck.Options{
+		Path:               f.path,
+		Mode:               f.mode,
+		Binaries:           f.binaries,
+		ImageArchives:      f.imageArchives,
+		ScanLevel:          f.scanLevel,
+		Platforms:          f.platforms,
+		Tags:               f.tags,
+		IncludeTests:       f.includeTests,
+		Packages:           packages,
+		Exclude:            excludes,
+		FromReport:         f.fromReport != "",
+		FailTestOnly:       f.testOnly == reportFail,
+		FailUnreachable:    f.unreachable == reportFail,
+		UnreviewedWarnDays: f.unreviewedWarnDays,
+		StrictReport:       f.strictReport,
+		FailOn:             f.failOn,
+		Parallelism:        f.parallelism,
+	}
+	var err error
+	if f.severityData != "" {
+		if opts.SeverityData, err = severity.Load(f.severityData); err != nil {
+			return govulncheck.Options{}, err
+		}
+	}
+	if f.minSeverity != "" {
+		if f.severityData == "" {
+			return govulncheck.Options{}, errors.New("'--min-severity' requires '--severity-data'")
+		}
+		if opts.MinSeverity, err = severity.ParseMinimum(f.minSeverity); err != nil {
+			return govulncheck.Options{}, err
+		}
+	}
+	if f.baselineFile != "" {
+		if opts.Baseline, err = govulncheck.ReadResults(f.baselineFile); err != nil {
+			return govulncheck.Options{}, err
+		}
+	}
+	if f.db != "" {
+		if opts.DB, err = vulndb.URL(f.db); err != nil {
+			return govulncheck.Options{}, err
+		}
+	}
+	return opts, nil
+}
+
+// validateMode checks that the flags are compatible with the scan mode, and with the evaluation of a saved report
+func validateMode(f *vulnCheckFlags) error {
+	// an empty set of build tags is the same as the default build context
+	hasTags := slices.ContainsFunc(f.tags, func(t string) bool {
+		return t != ""
+	})
+	// the settings of the scan which produced the report cannot be changed
+	if f.fromReport != "" && (f.mode != govulncheck.ModeSource || len(f.binaries) > 0 || len(f.imageArchives) > 0 || len(f.platforms) > 0 || hasTags ||
+		len(f.packages) > 0 || len(f.excludes) > 0 || f.includeTests || f.db != "" || f.cacheDir != "") {
+		return errors.New("'--from-report' cannot be combined with '--mode', '--binary', '--image-archive', '--platform', '--tags', '--packages', '--exclude', '--include-tests', '--db' or '--cache-dir'")
+	}
+	switch {
+	case f.mode != govulncheck.ModeSource && f.mode != govulncheck.ModeBinary:
+		return fmt.Errorf("invalid mode: '%s' (must be '%s' or '%s')", f.mode, govulncheck.ModeSource, govulncheck.ModeBinary)
+	case f.mode == govulncheck.ModeBinary && len(f.binaries) == 0 && len(f.imageArchives) == 0:
+		return fmt.Errorf("at least one '--binary' or '--image-archive' is required in '%s' mode", govulncheck.ModeBinary)
+	case f.mode == govulncheck.ModeSource && (len(f.binaries) > 0 || len(f.imageArchives) > 0):
+		return fmt.Errorf("'--binary' and '--image-archive' are only supported in '%s' mode", govulncheck.ModeBinary)
+	case f.mode == govulncheck.ModeBinary && (len(f.platforms) > 0 || hasTags):
+		return fmt.Errorf("'--platform' and '--tags' are only supported in '%s' mode", govulncheck.ModeSource)
+	case f.mode == govulncheck.ModeBinary && f.includeTests:
+		return fmt.Errorf("'--include-tests' is only supported in '%s' mode", govulncheck.ModeSource)
+	case f.mode == govulncheck.ModeBinary && (len(f.packages) > 0 || len(f.excludes) > 0):
+		return fmt.Errorf("'--packages' and '--exclude' are only supported in '%s' mode", govulncheck.ModeSource)
+	}
+	return nil
+}
+
+// validateReporting checks the flags which control how the vulnerabilities are reported
+func validateReporting(f *vulnCheckFlags) error {
+	if f.scanLevel != govulncheck.ScanLevelModule && f.scanLevel != govulncheck.ScanLevelPackage && f.scanLevel != govulncheck.ScanLevelSymbol {
+		return fmt.Errorf("invalid scan level: '%s' (must be '%s', '%s' or '%s')", f.scanLevel, govulncheck.ScanLevelModule, govulncheck.ScanLevelPackage, govulncheck.ScanLevelSymbol)
+	}
+	if f.unreachable != reportInfo && f.unreachable != reportFail {
+		return fmt.Errorf("invalid unreachable: '%s' (must be '%s' or '%s')", f.unreachable, reportInfo, reportFail)
+	}
+	if f.testOnly != reportInfo && f.testOnly != reportFail {
+		return fmt.Errorf("invalid test-only: '%s' (must be '%s' or '%s')", f.testOnly, reportInfo, reportFail)
+	}
+	if f.unreviewedWarnDays < 0 {
+		return fmt.Errorf("invalid unreviewed-warn-days: %d (must not be negative)", f.unreviewedWarnDays)
+	}
+	if f.showTraces != govulncheck.TracesFull && f.showTraces != govulncheck.TracesCompact && f.showTraces != govulncheck.TracesNone {
+		return fmt.Errorf("invalid show-traces: '%s' (must be '%s', '%s' or '%s')", f.showTraces, govulncheck.TracesFull, govulncheck.TracesCompact, govulncheck.TracesNone)
+	}
+	for _, selector := range f.failOn {
+		if !slices.Contains(govulncheck.FailOnSelectors, selector) {
+			return fmt.Errorf("invalid fail-on selector: '%s' (must be one of '%s')", selector, strings.Join(govulncheck.FailOnSelectors, "', '"))
+		}
+	}
+	return nil
+}
+
+// prepareSource selects the Go toolchain with which the code in the given path is analyzed, and checks its `go.mod`
+// (or `go.work`) file and its dependencies. It returns the environment variables to set when scanning the code.
+func prepareSource(ctx context.Context, logger *slog.Logger, path, gotoolchain string) ([]string, error) {
+	// use the Go toolchain specified in the `go.work` or `go.mod` file, unless explicitly specified with the flag.
+	// The `GOTOOLCHAIN` environment variable of the process is always overridden, since it is set to `local`
+	// in the golang images (including the image of the action)
+	if gotoolchain == "" {
+		var err error
+		if gotoolchain, err = toolchain.Select(path); err != nil {
+			return nil, failure.New(failure.KindBuild, err)
+		}
+	}
+	env := []string{"GOTOOLCHAIN=" + gotoolchain}
+	// check that there is a `go.mod` or a `go.work` file in the path
+	// (required by the underlying govulncheck command, but here we can collect insights of failures)
+	gomodCmd := exec.CommandContext(ctx, "go", "env", "GOMOD", "GOWORK")
+	gomodCmd.Dir = path
+	gomodCmd.Env = append(os.Environ(), env...)
+	output, err := gomodCmd.Output()
+	if err != nil {
+		return nil, failure.New(failure.KindBuild, fmt.Errorf("failed to get `go.mod` file: %w", err))
+	}
+	logger.Debug("`go.mod` and `go.work` files", "paths", strings.Fields(string(output)))
+	vendorDir, err := govulncheck.VendorDir(path)
+	if err != nil {
+		return nil, failure.New(failure.KindBuild, err)
+	}
+	if vendorDir != "" {
+		// the vendored dependencies are not in the module cache, and are checked when loading the packages
+		logger.Debug("dependencies are vendored, skipping `go mod verify`", "dir", vendorDir)
+	} else if err := toolchain.Verify(ctx, path, env); err != nil {
+		return nil, failure.New(failure.KindBuild, err)
+	}
+	goVersion, err := toolchain.Version(ctx, path, env)
+	if err != nil {
+		return nil, failure.New(failure.KindBuild, err)
+	}
+	logger.Info("using Go toolchain", "version", goVersion, "gotoolchain", gotoolchain)
+	// govulncheck matches the standard library against the version in the `GOVERSION` environment variable,
+	// and otherwise against the version of the Go toolchain in its own environment and working directory
+	env = append(env, "GOVERSION="+goVersion)
+	return env, nil
+}
+
+// exactArgs returns a validator of the positional arguments of a command, which reports a wrong number of arguments
+// as a configuration error
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+			return failure.New(failure.KindConfig, err)
+		}
+		return nil
+	}
+}
+
+// splitList splits the given values on commas and newlines (eg: multiline inputs of the action),
+// and ignores the blank items
+func splitList(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for item := range strings.FieldsFuncSeq(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func isEmpty(s string) bool {
+	return s == ""
+}
+
+func newLogger(out io.Writer, debug bool) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}
+	if debug {
+		opts.Level = slog.LevelDebug
+	}
+	handler := slog.NewTextHandler(out, opts)
+	return slog.New(handler)
+}
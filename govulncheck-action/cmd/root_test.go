@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareSource(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	// the golang images set `GOTOOLCHAIN=local`
+	t.Setenv("GOTOOLCHAIN", "local")
+	// use the local Go version in the `go` directive, so that no toolchain is downloaded
+	path := t.TempDir()
+	gomod := "module example.com/operator\n\ngo " + strings.TrimPrefix(runtime.Version(), "go") + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(path, "go.mod"), []byte(gomod), 0o600))
+
+	t.Run("toolchain of the go.mod file", func(t *testing.T) {
+		// when
+		env, err := prepareSource(context.Background(), logger, path, "")
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, env, "GOTOOLCHAIN="+runtime.Version())
+		assert.Contains(t, env, "GOVERSION="+runtime.Version())
+	})
+
+	t.Run("toolchain of the flag", func(t *testing.T) {
+		// when
+		env, err := prepareSource(context.Background(), logger, path, "local")
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, env, "GOTOOLCHAIN=local")
+	})
+
+	t.Run("no go.mod file", func(t *testing.T) {
+		// when
+		_, err := prepareSource(context.Background(), logger, t.TempDir(), "")
+		// then
+		require.Error(t, err)
+		assert.Equal(t, failure.KindBuild, failure.KindOf(err))
+	})
+}
+
+func TestVulnCheckCmdBinaryMode(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".govulncheck.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("packages:\n  - ./pkg/...\nexclude:\n  - ./test/...\n"), 0o600))
+	binary := filepath.Join(dir, "operator") // does not exist
+
+	t.Run("packages and excludes of the config file are ignored", func(t *testing.T) {
+		// given
+		cmd := NewVulnCheckCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--config", configFile, "--path", dir, "--mode", "binary", "--binary", binary})
+		// when
+		err := cmd.Execute()
+		// then the binary is scanned
+		require.ErrorContains(t, err, "invalid binary path")
+	})
+
+	for _, flag := range []string{"--packages", "--exclude"} {
+		t.Run(flag+" flag is rejected", func(t *testing.T) {
+			// given
+			cmd := NewVulnCheckCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs([]string{"--config", configFile, "--path", dir, "--mode", "binary", "--binary", binary, flag, "./pkg/..."})
+			// when
+			err := cmd.Execute()
+			// then
+			require.EqualError(t, err, "'--packages' and '--exclude' are only supported in 'source' mode")
+			assert.Equal(t, failure.KindConfig, failure.KindOf(err))
+		})
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	// the default values of the flags
+	newFlags := func() *vulnCheckFlags {
+		return &vulnCheckFlags{
+			path:        ".",
+			mode:        govulncheck.ModeSource,
+			scanLevel:   govulncheck.ScanLevelSymbol,
+			unreachable: reportInfo,
+			testOnly:    reportFail,
+			showTraces:  govulncheck.TracesCompact,
+			failOn:      []string{govulncheck.FailOnAll},
+			parallelism: 1,
+		}
+	}
+	config := configuration.Configuration{
+		Packages: []string{"./pkg/..."},
+		Exclude:  []string{"test"},
+	}
+
+	t.Run("packages and excludes of the config file in source mode", func(t *testing.T) {
+		// given
+		f := newFlags()
+		f.excludes = []string{"hack", ""}
+		// when
+		opts, err := validateOptions(f, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./pkg/..."}, opts.Packages)
+		assert.Equal(t, []string{"hack", "test"}, opts.Exclude)
+		assert.Equal(t, []string{govulncheck.FailOnAll}, opts.FailOn)
+		assert.Equal(t, 1, opts.Parallelism)
+	})
+
+	t.Run("packages of the flags take precedence over the config file", func(t *testing.T) {
+		// given
+		f := newFlags()
+		f.packages = []string{"./cmd/..."}
+		// when
+		opts, err := validateOptions(f, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./cmd/..."}, opts.Packages)
+		assert.Equal(t, []string{"test"}, opts.Exclude)
+	})
+
+	t.Run("packages and excludes of the config file in binary mode", func(t *testing.T) {
+		// given
+		f := newFlags()
+		f.mode = govulncheck.ModeBinary
+		f.binaries = []string{"bin/operator", "", "bin/manager,bin/webhook", "bin/cli\n bin/agent \n\n"}
+		// when
+		opts, err := validateOptions(f, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bin/operator", "bin/manager", "bin/webhook", "bin/cli", "bin/agent"}, opts.Binaries)
+		assert.Empty(t, opts.Packages)
+		assert.Empty(t, opts.Exclude)
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		testCases := map[string]struct {
+			update   func(f *vulnCheckFlags)
+			expected string
+		}{
+			"parallelism": {
+				update:   func(f *vulnCheckFlags) { f.parallelism = 0 },
+				expected: "invalid parallelism: 0 (must be at least 1)",
+			},
+			"mode": {
+				update:   func(f *vulnCheckFlags) { f.mode = "image" },
+				expected: "invalid mode: 'image' (must be 'source' or 'binary')",
+			},
+			"binary mode without binaries": {
+				update:   func(f *vulnCheckFlags) { f.mode = govulncheck.ModeBinary },
+				expected: "at least one '--binary' or '--image-archive' is required in 'binary' mode",
+			},
+			"tags in binary mode": {
+				update: func(f *vulnCheckFlags) {
+					f.mode = govulncheck.ModeBinary
+					f.binaries = []string{"bin/operator"}
+					f.tags = []string{"e2e"}
+				},
+				expected: "'--platform' and '--tags' are only supported in 'source' mode",
+			},
+			"from report with cache": {
+				update: func(f *vulnCheckFlags) {
+					f.fromReport = "report.json"
+					f.cacheDir = "cache"
+				},
+				expected: "'--from-report' cannot be combined with '--mode', '--binary', '--image-archive', '--platform', '--tags', '--packages', '--exclude', '--include-tests', '--db' or '--cache-dir'",
+			},
+			"scan level": {
+				update:   func(f *vulnCheckFlags) { f.scanLevel = "function" },
+				expected: "invalid scan level: 'function' (must be 'module', 'package' or 'symbol')",
+			},
+			"fail-on selector": {
+				update:   func(f *vulnCheckFlags) { f.failOn = []string{"bogus"} },
+				expected: "invalid fail-on selector: 'bogus' (must be one of 'all', 'stdlib', 'third-party', 'fixable', 'unfixable', 'reachable')",
+			},
+			"min severity without severity data": {
+				update:   func(f *vulnCheckFlags) { f.minSeverity = "high" },
+				expected: "'--min-severity' requires '--severity-data'",
+			},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				// given
+				f := newFlags()
+				tc.update(f)
+				// when
+				_, err := validateOptions(f, config)
+				// then
+				require.EqualError(t, err, tc.expected)
+			})
+		}
+	})
+}
+
+func TestUnexpectedArgs(t *testing.T) {
+	testCases := map[string][]string{
+		"vuln-check":  {"--config", ".govulncheck.yaml", "extra"},
+		"diff":        {"diff", "old.json"},
+		"db snapshot": {"db", "snapshot", "--dir", "db", "extra"},
+	}
+	for name, args := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// given
+			cmd := NewVulnCheckCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(args)
+			// when
+			err := cmd.Execute()
+			// then
+			require.ErrorContains(t, err, "accepts")
+			assert.Equal(t, failure.KindConfig, failure.KindOf(err))
+		})
+	}
+}
@@ -1,23 +1,6 @@
 #!/bin/bash
 
-# Read the toolchain directive from the scanned project's go.mod so that govulncheck
-# analyses the code against the Go stdlib version the project actually uses, not the
-# Go version baked into this container.
-# See https://go.dev/doc/toolchain
-GOMOD_TOOLCHAIN=$(grep '^toolchain ' go.mod 2>/dev/null | awk '{print $2}')
-GOMOD_GO=$(grep '^go ' go.mod 2>/dev/null | awk '{print $2}')
-if [ -n "$GOMOD_TOOLCHAIN" ]; then
-    export GOTOOLCHAIN="$GOMOD_TOOLCHAIN"
-elif [ -n "$GOMOD_GO" ]; then
-    export GOTOOLCHAIN="go${GOMOD_GO}"
-else
-    export GOTOOLCHAIN=auto
-fi
-
-go mod verify
-
-# Check the version of Go that will be used for scanning
-go version
-
 # Run the govulncheck command
-govulncheckx "$@"
\ No newline at end of file
+# (the Go toolchain is selected by the command itself, based on the `toolchain` or `go` directive
+# of the scanned project's `go.work` or `go.mod` file, see https://go.dev/doc/toolchain)
+exec govulncheckx "$@"
@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/toolchain"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/vulndb"
+)
+
+// version is the version of the cache format, to change when the content or the key of the cache entries changes
+// after a release
+const version = "v1"
+
+// Cache stores the govulncheck reports in a directory, keyed by a hash of
+// the `go.mod` and `go.sum` files and the build list (or the binary), the Go toolchain version,
+// the vulnerability database last modification time and the scan settings.
+// At the `package` and `symbol` levels, the Go files of the scanned packages
+// are also part of the key, since a change in the source code can import a
+// vulnerable package or call a vulnerable symbol.
+type Cache struct {
+	dir string
+	// goVersion returns the version of the Go toolchain used to scan the target
+	goVersion func(ctx context.Context, target govulncheck.Target) (string, error)
+	// buildList returns the build list of the target (which depends on all the `go.mod` files of a workspace)
+	buildList func(ctx context.Context, target govulncheck.Target) (string, error)
+	// sourceFiles returns the paths of the Go files analyzed when scanning the target
+	sourceFiles func(ctx context.Context, target govulncheck.Target) ([]string, error)
+	// dbLastModified returns the time when the vulnerability database was last modified
+	dbLastModified func(ctx context.Context, db string) (time.Time, error)
+}
+
+// New returns a new cache which stores the reports in the given directory
+func New(dir string, client *http.Client) *Cache {
+	return &Cache{
+		dir:         dir,
+		goVersion:   goVersion,
+		buildList:   govulncheck.BuildList,
+		sourceFiles: govulncheck.SourceFiles,
+		dbLastModified: func(ctx context.Context, db string) (time.Time, error) {
+			return vulndb.LastModified(ctx, client, db)
+		},
+	}
+}
+
+// Scan returns a ScanFunc which returns the cached report of the target if it exists,
+// otherwise it runs the given scan and stores its report in the cache.
+func (c *Cache) Scan(scan govulncheck.ScanFunc) govulncheck.ScanFunc {
+	return func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+		key, err := c.key(ctx, target)
+		if err != nil {
+			// the report can still be computed, but not cached
+			logger.Warn("failed to compute the cache key", "error", err.Error())
+			return scan(ctx, logger, target)
+		}
+		path := filepath.Join(c.dir, key+".json")
+		report, err := c.load(path)
+		switch {
+		case err == nil:
+			logger.Info("using cached report", "path", path)
+			return report, nil
+		case !errors.Is(err, os.ErrNotExist):
+			logger.Warn("failed to read the cached report", "path", path, "error", err.Error())
+		}
+		report, err = scan(ctx, logger, target)
+		if err != nil || report == nil {
+			return report, err
+		}
+		if err := c.store(path, report); err != nil {
+			logger.Warn("failed to store the report in the cache", "path", path, "error", err.Error())
+		} else {
+			logger.Debug("report stored in the cache", "path", path)
+		}
+		return report, nil
+	}
+}
+
+// key returns the cache key of the given target
+func (c *Cache) key(ctx context.Context, target govulncheck.Target) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "cache:%s\n", version)
+	fmt.Fprintf(h, "scanner:%s\n", scannerVersion())
+	settings := target
+	if settings.Image != "" {
+		// the binary is extracted in a temporary directory, whose path must not invalidate the cached reports
+		settings.Dir = ""
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal target: %w", err)
+	}
+	fmt.Fprintf(h, "target:%s\n", data)
+	goVersion, err := c.goVersion(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "go:%s\n", goVersion)
+	lastModified, err := c.dbLastModified(ctx, cmp.Or(target.DB, vulndb.DefaultSource))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "db:%s\n", lastModified.UTC().Format(time.RFC3339))
+	for _, f := range files(target) {
+		if err := hashFile(h, filepath.Base(f), f); err != nil {
+			return "", err
+		}
+	}
+	if target.Binary == "" && target.VendorDir == "" {
+		// the requirements of the other modules of a workspace are part of the build list
+		// (when the dependencies are vendored, they are all listed in the `modules.txt` file)
+		buildList, err := c.buildList(ctx, target)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "modules:%s\n", buildList)
+	}
+	if target.Binary == "" && cmp.Or(target.ScanLevel, govulncheck.ScanLevelSymbol) != govulncheck.ScanLevelModule {
+		sources, err := c.sourceFiles(ctx, target)
+		if err != nil {
+			return "", err
+		}
+		for _, f := range sources {
+			name, err := filepath.Rel(target.Dir, f)
+			if err != nil {
+				return "", fmt.Errorf("failed to get relative path of '%s': %w", f, err)
+			}
+			if err := hashFile(h, filepath.ToSlash(name), f); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// files returns the files whose contents are part of the cache key of the given target
+func files(target govulncheck.Target) []string {
+	if target.Binary != "" {
+		return []string{target.BinaryPath()}
+	}
+	files := []string{
+		filepath.Join(target.Dir, "go.mod"),
+		filepath.Join(target.Dir, "go.sum"),
+	}
+	for _, env := range target.Env {
+		if gowork, found := strings.CutPrefix(env, "GOWORK="); found {
+			files = append(files, gowork, gowork+".sum")
+		}
+	}
+	if target.VendorDir != "" {
+		files = append(files, filepath.Join(target.VendorDir, "modules.txt"))
+	}
+	return files
+}
+
+// hashFile writes the given name and the contents of the file in the hash (or only its name if the file does not exist)
+func hashFile(h io.Writer, name, path string) error {
+	fmt.Fprintf(h, "file:%s\n", name)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// eg: no `go.sum` file when the module has no dependency
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	return nil
+}
+
+// load reads the report in the given path
+func (c *Cache) load(path string) (*govulncheck.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return govulncheck.ParseReport(f)
+}
+
+// store writes the report in the given path, using a temporary file so that concurrent scans never read a partial report
+func (c *Cache) store(path string, report *govulncheck.Report) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, "report-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := report.Encode(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// goVersion returns the version of the Go toolchain used in the directory of the target
+func goVersion(ctx context.Context, target govulncheck.Target) (string, error) {
+	return toolchain.Version(ctx, target.Dir, target.Env)
+}
+
+// scannerVersion returns the version of the govulncheck library embedded in this binary
+func scannerVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "golang.org/x/vuln" {
+				return dep.Version
+			}
+		}
+	}
+	return "unknown"
+}
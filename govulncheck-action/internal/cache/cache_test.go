@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	lastModified := time.Date(2025, 4, 24, 18, 14, 57, 0, time.UTC)
+
+	newCache := func(t *testing.T) *Cache {
+		return &Cache{
+			dir: t.TempDir(),
+			goVersion: func(_ context.Context, _ govulncheck.Target) (string, error) {
+				return "go1.26.0", nil
+			},
+			dbLastModified: func(_ context.Context, db string) (time.Time, error) {
+				assert.Equal(t, "https://vuln.go.dev", db)
+				return lastModified, nil
+			},
+			buildList: func(_ context.Context, _ govulncheck.Target) (string, error) {
+				return "example.com/operator\ngolang.org/x/net v0.38.0\n", nil
+			},
+			sourceFiles: func(_ context.Context, target govulncheck.Target) ([]string, error) {
+				return filepath.Glob(filepath.Join(target.Dir, "*.go"))
+			},
+		}
+	}
+	newTarget := func(t *testing.T) govulncheck.Target {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/operator\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte("golang.org/x/net v0.38.0 h1:...\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o600))
+		return govulncheck.Target{Dir: dir}
+	}
+	// newScan returns a scan func which counts its calls
+	newScan := func(calls *int) govulncheck.ScanFunc {
+		return func(_ context.Context, _ *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			*calls++
+			return &govulncheck.Report{
+				Config: &govulncheck.Config{
+					ScannerName:    "govulncheck",
+					ScannerVersion: "v1.1.4",
+					DBLastModified: &lastModified,
+				},
+				Finding: map[string][]*govulncheck.Finding{
+					"GO-2025-3595": {{Osv: "GO-2025-3595", FixedVersion: "v0.38.0"}},
+				},
+				OSV: map[string]*govulncheck.OSV{
+					"GO-2025-3595": {ID: "GO-2025-3595", Summary: "Incorrect Neutralization of Input During Web Page Generation in x/net"},
+				},
+			}, nil
+		}
+	}
+
+	t.Run("report is reused when nothing changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		// when
+		first, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		second, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		// then
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+		entries, err := os.ReadDir(c.dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("report is not reused when go.sum changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(target.Dir, "go.sum"), []byte("golang.org/x/net v0.39.0 h1:...\n"), 0o600))
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("report is not reused when the source code changed", func(t *testing.T) {
+		for _, level := range []string{govulncheck.ScanLevelSymbol, govulncheck.ScanLevelPackage} {
+			t.Run(level, func(t *testing.T) {
+				// given
+				c := newCache(t)
+				target := newTarget(t)
+				target.ScanLevel = level
+				calls := 0
+				scan := c.Scan(newScan(&calls))
+				_, err := scan(context.Background(), logger, target)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(target.Dir, "main.go"), []byte("package main\n\nimport \"golang.org/x/net/html\"\n\nfunc main() { html.Parse(nil) }\n"), 0o600))
+				// when
+				_, err = scan(context.Background(), logger, target)
+				// then
+				require.NoError(t, err)
+				assert.Equal(t, 2, calls)
+			})
+		}
+	})
+
+	t.Run("report is reused at module level when the source code changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		c.sourceFiles = func(_ context.Context, _ govulncheck.Target) ([]string, error) {
+			return nil, errors.New("source files must not be listed at module level")
+		}
+		target := newTarget(t)
+		target.ScanLevel = govulncheck.ScanLevelModule
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(target.Dir, "main.go"), []byte("package main\n\nfunc main() { println() }\n"), 0o600))
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("report is not reused when the requirements of another workspace module changed", func(t *testing.T) {
+		// given a workspace in which the common module does not require the library yet
+		// (replaced by a local directory, so that the build list is computed offline)
+		t.Setenv("GOFLAGS", "") // `-mod=mod` is not supported in workspace mode
+		path := t.TempDir()
+		for name, contents := range map[string]string{
+			"go.work":          "go 1.26.0\n\nuse (\n\t./operator\n\t./common\n)\n\nreplace example.com/lib => ./lib\n",
+			"operator/go.mod":  "module example.com/operator\n\ngo 1.26.0\n",
+			"operator/main.go": "package main\n\nfunc main() {}\n",
+			"common/go.mod":    "module example.com/common\n\ngo 1.26.0\n",
+			"lib/go.mod":       "module example.com/lib\n\ngo 1.26.0\n",
+		} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(path, name)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(contents), 0o600))
+		}
+		c := newCache(t)
+		c.buildList = govulncheck.BuildList
+		target := govulncheck.Target{
+			Dir:       filepath.Join(path, "operator"),
+			ScanLevel: govulncheck.ScanLevelModule,
+			Env:       []string{"GOWORK=" + filepath.Join(path, "go.work")},
+		}
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		_, err = scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+		require.NoError(t, os.WriteFile(filepath.Join(path, "common", "go.mod"), []byte("module example.com/common\n\ngo 1.26.0\n\nrequire example.com/lib v1.0.0\n"), 0o600))
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("report is not reused when the database changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		c.dbLastModified = func(_ context.Context, _ string) (time.Time, error) {
+			return lastModified.Add(time.Hour), nil
+		}
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("report is not reused when the Go version changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		c.goVersion = func(_ context.Context, _ govulncheck.Target) (string, error) {
+			return "go1.26.1", nil
+		}
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("report is not reused when the scan settings changed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		target.ScanLevel = govulncheck.ScanLevelPackage
+		// when
+		_, err = scan(context.Background(), logger, target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("report is not cached when the key cannot be computed", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		c.dbLastModified = func(_ context.Context, _ string) (time.Time, error) {
+			return time.Time{}, errors.New("mock error")
+		}
+		target := newTarget(t)
+		calls := 0
+		scan := c.Scan(newScan(&calls))
+		// when
+		_, err := scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		_, err = scan(context.Background(), logger, target)
+		require.NoError(t, err)
+		// then
+		assert.Equal(t, 2, calls)
+		entries, err := os.ReadDir(c.dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("failed scan is not cached", func(t *testing.T) {
+		// given
+		c := newCache(t)
+		target := newTarget(t)
+		scan := c.Scan(func(_ context.Context, _ *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return nil, errors.New("mock error")
+		})
+		// when
+		_, err := scan(context.Background(), logger, target)
+		// then
+		require.EqualError(t, err, "mock error")
+		entries, err := os.ReadDir(c.dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
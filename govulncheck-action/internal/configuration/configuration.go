@@ -9,6 +9,10 @@ import (
 
 type Configuration struct {
 	IgnoredVulnerabilities []*Vulnerability `yaml:"ignored-vulnerabilities"`
+	// Packages are the patterns of the packages to scan (`./...` if empty)
+	Packages []string `yaml:"packages"`
+	// Exclude are the globs of the directories of the packages to exclude from the scan
+	Exclude []string `yaml:"exclude"`
 }
 
 type Vulnerability struct {
@@ -89,6 +89,29 @@ func TestNewConfiguration(t *testing.T) {
 		assert.Equal(t, "https://pkg.go.dev/vuln/GO-2025-3563", c.IgnoredVulnerabilities[2].Info)
 	})
 
+	t.Run("packages and excludes", func(t *testing.T) {
+		// given
+		tempFile, err := os.CreateTemp("", "ignored-vuln-*.yaml")
+		require.NoError(t, err)
+		content := `packages:
+  - ./cmd/...
+  - ./pkg/...
+exclude:
+  - hack
+  - pkg/*/generated
+ignored-vulnerabilities: []`
+		_, err = tempFile.WriteString(content)
+		require.NoError(t, err)
+
+		// when
+		c, err := configuration.New(tempFile.Name())
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./cmd/...", "./pkg/..."}, c.Packages)
+		assert.Equal(t, []string{"hack", "pkg/*/generated"}, c.Exclude)
+		assert.Empty(t, c.IgnoredVulnerabilities)
+	})
+
 	t.Run("invalid file", func(t *testing.T) {
 		// given
 		tempFile, err := os.CreateTemp("", "ignored-vuln-*.yaml")
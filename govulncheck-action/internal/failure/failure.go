@@ -0,0 +1,94 @@
+package failure
+
+import (
+	"context"
+	"errors"
+)
+
+// Kind is the kind of failure, which determines the exit code of the command
+type Kind int
+
+const (
+	// KindUnknown is an unexpected failure
+	KindUnknown Kind = iota
+	// KindVulnerabilities means that vulnerabilities were found (or that some ignored vulnerabilities are outdated)
+	KindVulnerabilities
+	// KindConfig means that the configuration (file or flags) is invalid
+	KindConfig
+	// KindDB means that the vulnerability database is unreachable
+	KindDB
+	// KindBuild means that the packages could not be loaded or built
+	KindBuild
+	// KindTimeout means that the scan timed out or was cancelled
+	KindTimeout
+)
+
+// exitCodes are the exit codes of the command for each kind of failure
+var exitCodes = map[Kind]int{
+	KindVulnerabilities: 1,
+	KindConfig:          2,
+	KindDB:              3,
+	KindBuild:           4,
+	KindTimeout:         5,
+	KindUnknown:         6,
+}
+
+// String returns the name of the kind of failure
+func (k Kind) String() string {
+	switch k {
+	case KindVulnerabilities:
+		return "vulnerabilities"
+	case KindConfig:
+		return "config"
+	case KindDB:
+		return "db"
+	case KindBuild:
+		return "build"
+	case KindTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is an error with a kind of failure
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error with the given kind of failure
+func New(kind Kind, err error) error {
+	return &Error{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+// KindOf returns the kind of failure of the given error.
+// Errors caused by a context deadline or cancellation are timeouts, unless they have another kind.
+func KindOf(err error) Kind {
+	if e := (&Error{}); errors.As(err, &e) {
+		return e.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return KindTimeout
+	}
+	return KindUnknown
+}
+
+// ExitCode returns the exit code of the command for the given error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return exitCodes[KindOf(err)]
+}
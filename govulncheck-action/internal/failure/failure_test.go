@@ -0,0 +1,71 @@
+package failure_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+
+	testCases := map[string]struct {
+		err          error
+		expectedKind failure.Kind
+		expectedCode int
+	}{
+		"no error": {
+			err:          nil,
+			expectedKind: failure.KindUnknown,
+			expectedCode: 0,
+		},
+		"vulnerabilities": {
+			err:          failure.New(failure.KindVulnerabilities, errors.New("2 vulnerabilities found")),
+			expectedKind: failure.KindVulnerabilities,
+			expectedCode: 1,
+		},
+		"config": {
+			err:          failure.New(failure.KindConfig, errors.New("invalid mode")),
+			expectedKind: failure.KindConfig,
+			expectedCode: 2,
+		},
+		"wrapped db": {
+			err:          fmt.Errorf("scan failed: %w", failure.New(failure.KindDB, errors.New("fetching vulnerabilities"))),
+			expectedKind: failure.KindDB,
+			expectedCode: 3,
+		},
+		"build": {
+			err:          failure.New(failure.KindBuild, errors.New("loading packages")),
+			expectedKind: failure.KindBuild,
+			expectedCode: 4,
+		},
+		"deadline exceeded": {
+			err:          fmt.Errorf("scan failed: %w", context.DeadlineExceeded),
+			expectedKind: failure.KindTimeout,
+			expectedCode: 5,
+		},
+		"cancelled": {
+			err:          context.Canceled,
+			expectedKind: failure.KindTimeout,
+			expectedCode: 5,
+		},
+		"unknown": {
+			err:          errors.New("mock error"),
+			expectedKind: failure.KindUnknown,
+			expectedCode: 6,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.err != nil {
+				assert.Equal(t, tc.expectedKind, failure.KindOf(tc.err))
+			}
+			assert.Equal(t, tc.expectedCode, failure.ExitCode(tc.err))
+		})
+	}
+}
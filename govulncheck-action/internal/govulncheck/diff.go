@@ -0,0 +1,176 @@
+package govulncheck
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Diff contains the differences between the vulnerabilities of two scans
+type Diff struct {
+	// Added are the vulnerabilities which are only found in the new scan
+	Added []*Vulnerability `json:"added"`
+	// Removed are the vulnerabilities which are only found in the old scan (eg: fixed by a dependency upgrade)
+	Removed []*Vulnerability `json:"removed"`
+	// Changed are the vulnerabilities which are found in both scans, but with different call sites or affected versions
+	Changed []Change `json:"changed"`
+}
+
+// Change contains the differences between the findings of a vulnerability in two scans
+type Change struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	// AddedCallSites are the call sites which are only found in the new scan
+	// (eg: `github.com/codeready-toolchain/host-operator/pkg/proxy.ServeHTTP (pkg/proxy/proxy.go:42:12) -> net/http/internal.(*chunkedReader).Read`)
+	AddedCallSites []string `json:"added_call_sites,omitempty"`
+	// RemovedCallSites are the call sites which are only found in the old scan
+	RemovedCallSites []string `json:"removed_call_sites,omitempty"`
+	// AddedAffected are the affected module versions (with their fixed versions) which are only found in the new scan
+	AddedAffected []Affected `json:"added_affected,omitempty"`
+	// RemovedAffected are the affected module versions (with their fixed versions) which are only found in the old scan
+	RemovedAffected []Affected `json:"removed_affected,omitempty"`
+}
+
+// IsEmpty returns true if there are no differences
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffVulnerabilities returns the differences between the vulnerabilities of the old and the new scans, sorted by ID
+func DiffVulnerabilities(oldVulns, newVulns []*Vulnerability) Diff {
+	diff := Diff{
+		Added:   []*Vulnerability{},
+		Removed: []*Vulnerability{},
+		Changed: []Change{},
+	}
+	old := make(map[string]*Vulnerability, len(oldVulns))
+	for _, v := range oldVulns {
+		old[v.ID] = v
+	}
+	found := make(map[string]bool, len(newVulns))
+	for _, v := range newVulns {
+		found[v.ID] = true
+		o, exists := old[v.ID]
+		if !exists {
+			diff.Added = append(diff.Added, v)
+			continue
+		}
+		oldCallSites, newCallSites := getCallSites(o.CallStacks), getCallSites(v.CallStacks)
+		change := Change{
+			ID:               v.ID,
+			Summary:          v.Summary,
+			AddedCallSites:   subtractCallSites(newCallSites, oldCallSites),
+			RemovedCallSites: subtractCallSites(oldCallSites, newCallSites),
+			AddedAffected:    subtractAffected(v.Affected, o.Affected),
+			RemovedAffected:  subtractAffected(o.Affected, v.Affected),
+		}
+		if len(change.AddedCallSites) > 0 || len(change.RemovedCallSites) > 0 || len(change.AddedAffected) > 0 || len(change.RemovedAffected) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, v := range oldVulns {
+		if !found[v.ID] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	byID := func(a, b *Vulnerability) int { return strings.Compare(a.ID, b.ID) }
+	slices.SortFunc(diff.Added, byID)
+	slices.SortFunc(diff.Removed, byID)
+	slices.SortFunc(diff.Changed, func(a, b Change) int { return strings.Compare(a.ID, b.ID) })
+	return diff
+}
+
+// getCallSites returns the call sites of the call stacks, keyed by their binary, the file and function of their entry point,
+// and their vulnerable symbol (so that a call which only moved within its function is the same call site)
+func getCallSites(stacks []CallStack) map[string]string {
+	callSites := make(map[string]string, len(stacks))
+	for _, stack := range stacks {
+		if len(stack.Frames) == 0 {
+			continue
+		}
+		entry, vulnerable := stack.Frames[0], stack.Frames[len(stack.Frames)-1]
+		file, _, _ := strings.Cut(entry.Position, ":")
+		key := strings.Join([]string{stack.Binary, file, entry.Function, vulnerable.Function}, "\x00")
+		if _, exists := callSites[key]; exists {
+			continue
+		}
+		b := &strings.Builder{}
+		if stack.Binary != "" {
+			fmt.Fprintf(b, "%s: ", stack.Binary)
+		}
+		b.WriteString(entry.Function)
+		if entry.Position != "" {
+			fmt.Fprintf(b, " (%s)", entry.Position)
+		}
+		if len(stack.Frames) > 1 {
+			fmt.Fprintf(b, " -> %s", vulnerable.Function)
+		}
+		callSites[key] = b.String()
+	}
+	return callSites
+}
+
+// subtractCallSites returns the call sites which are in the first collection but not in the second one (sorted)
+func subtractCallSites(first, second map[string]string) []string {
+	var result []string
+	for key, callSite := range first {
+		if _, found := second[key]; !found {
+			result = append(result, callSite)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// subtractAffected returns the affected versions which are in the first slice but not in the second one
+func subtractAffected(first, second []Affected) []Affected {
+	var result []Affected
+	for _, a := range first {
+		if !slices.Contains(second, a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// PrintDiff prints the differences between the vulnerabilities of two scans
+func PrintDiff(stdout io.Writer, diff Diff) {
+	if diff.IsEmpty() {
+		fmt.Fprintln(stdout, "No differences between the vulnerabilities of the scans")
+		return
+	}
+	if len(diff.Added) > 0 {
+		fmt.Fprintln(stdout, "Added vulnerabilities:")
+		for _, v := range diff.Added {
+			fmt.Fprintf(stdout, "  %s: %s\n", v.ID, v.Summary)
+		}
+		fmt.Fprintln(stdout, "")
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Fprintln(stdout, "Removed vulnerabilities:")
+		for _, v := range diff.Removed {
+			fmt.Fprintf(stdout, "  %s: %s\n", v.ID, v.Summary)
+		}
+		fmt.Fprintln(stdout, "")
+	}
+	if len(diff.Changed) > 0 {
+		fmt.Fprintln(stdout, "Changed vulnerabilities:")
+		for _, c := range diff.Changed {
+			fmt.Fprintf(stdout, "  %s: %s\n", c.ID, c.Summary)
+			for _, a := range c.RemovedAffected {
+				fmt.Fprintf(stdout, "    - affected: %s\n", a)
+			}
+			for _, a := range c.AddedAffected {
+				fmt.Fprintf(stdout, "    + affected: %s\n", a)
+			}
+			for _, s := range c.RemovedCallSites {
+				fmt.Fprintf(stdout, "    - call site: %s\n", s)
+			}
+			for _, s := range c.AddedCallSites {
+				fmt.Fprintf(stdout, "    + call site: %s\n", s)
+			}
+		}
+		fmt.Fprintln(stdout, "")
+	}
+}
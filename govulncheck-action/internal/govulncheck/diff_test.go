@@ -0,0 +1,126 @@
+package govulncheck
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffVulnerabilities(t *testing.T) {
+	callStack := func(entry, position string) CallStack {
+		return CallStack{Frames: []Frame{
+			{Module: "github.com/codeready-toolchain/host-operator", Function: entry, Position: position},
+			{Module: "stdlib", Function: "net/http/internal.(*chunkedReader).Read"},
+		}}
+	}
+	oldVulns := []*Vulnerability{
+		{
+			ID:         "GO-2025-3563",
+			Summary:    "Request smuggling due to acceptance of invalid chunked data in net/http",
+			Affected:   []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+			CallStacks: []CallStack{callStack("github.com/codeready-toolchain/host-operator/pkg/proxy.ServeHTTP", "pkg/proxy/proxy.go:42:12")},
+		},
+		{
+			ID:       "GO-2025-3595",
+			Summary:  "Incorrect Neutralization of Input During Web Page Generation in x/net",
+			Affected: []Affected{{Module: "golang.org/x/net", Version: "v0.33.0", FixedVersion: "v0.38.0"}},
+		},
+		{
+			ID:       "GO-2025-3547",
+			Summary:  "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+			Affected: []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}},
+		},
+	}
+
+	t.Run("no differences", func(t *testing.T) {
+		// when
+		diff := DiffVulnerabilities(oldVulns, oldVulns)
+		// then
+		assert.True(t, diff.IsEmpty())
+		var buf bytes.Buffer
+		PrintDiff(&buf, diff)
+		assert.Equal(t, "No differences between the vulnerabilities of the scans\n", buf.String())
+	})
+
+	t.Run("added, removed and changed vulnerabilities", func(t *testing.T) {
+		// given
+		newVulns := []*Vulnerability{
+			{
+				ID:       "GO-2025-3563",
+				Summary:  "Request smuggling due to acceptance of invalid chunked data in net/http",
+				Affected: []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+				CallStacks: []CallStack{
+					// the call moved within its function
+					callStack("github.com/codeready-toolchain/host-operator/pkg/proxy.ServeHTTP", "pkg/proxy/proxy.go:57:12"),
+					callStack("github.com/codeready-toolchain/host-operator/pkg/proxy.Start", "pkg/proxy/proxy.go:120:8"),
+				},
+			},
+			{
+				ID:       "GO-2025-3547",
+				Summary:  "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+				Affected: []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.11", FixedVersion: "v1.30.12"}},
+			},
+			{
+				ID:      "GO-2025-3487",
+				Summary: "Potential denial of service in golang.org/x/crypto",
+			},
+		}
+		// when
+		diff := DiffVulnerabilities(oldVulns, newVulns)
+		// then
+		assert.Equal(t, []*Vulnerability{newVulns[2]}, diff.Added)
+		assert.Equal(t, []*Vulnerability{oldVulns[1]}, diff.Removed)
+		assert.Equal(t, []Change{
+			{
+				ID:              "GO-2025-3547",
+				Summary:         "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+				AddedAffected:   []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.11", FixedVersion: "v1.30.12"}},
+				RemovedAffected: []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}},
+			},
+			{
+				ID:             "GO-2025-3563",
+				Summary:        "Request smuggling due to acceptance of invalid chunked data in net/http",
+				AddedCallSites: []string{"github.com/codeready-toolchain/host-operator/pkg/proxy.Start (pkg/proxy/proxy.go:120:8) -> net/http/internal.(*chunkedReader).Read"},
+			},
+		}, diff.Changed)
+
+		var buf bytes.Buffer
+		PrintDiff(&buf, diff)
+		assert.Equal(t, `Added vulnerabilities:
+  GO-2025-3487: Potential denial of service in golang.org/x/crypto
+
+Removed vulnerabilities:
+  GO-2025-3595: Incorrect Neutralization of Input During Web Page Generation in x/net
+
+Changed vulnerabilities:
+  GO-2025-3547: Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes
+    - affected: k8s.io/kubernetes@v1.30.10 (no fix available)
+    + affected: k8s.io/kubernetes@v1.30.11 (fixed in v1.30.12)
+  GO-2025-3563: Request smuggling due to acceptance of invalid chunked data in net/http
+    + call site: github.com/codeready-toolchain/host-operator/pkg/proxy.Start (pkg/proxy/proxy.go:120:8) -> net/http/internal.(*chunkedReader).Read
+
+`, buf.String())
+	})
+
+	t.Run("call sites in binaries", func(t *testing.T) {
+		// given
+		stack := callStack("main.main", "")
+		stack.Binary = "bin/host-operator"
+		newVulns := []*Vulnerability{oldVulns[0], oldVulns[1], oldVulns[2]}
+		changed := *oldVulns[0]
+		changed.CallStacks = []CallStack{stack}
+		newVulns[0] = &changed
+		// when
+		diff := DiffVulnerabilities(oldVulns, newVulns)
+		// then
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Removed)
+		assert.Equal(t, []Change{{
+			ID:               "GO-2025-3563",
+			Summary:          "Request smuggling due to acceptance of invalid chunked data in net/http",
+			AddedCallSites:   []string{"bin/host-operator: main.main -> net/http/internal.(*chunkedReader).Read"},
+			RemovedCallSites: []string{"github.com/codeready-toolchain/host-operator/pkg/proxy.ServeHTTP (pkg/proxy/proxy.go:42:12) -> net/http/internal.(*chunkedReader).Read"},
+		}}, diff.Changed)
+	})
+}
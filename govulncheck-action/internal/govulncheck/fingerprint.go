@@ -0,0 +1,54 @@
+package govulncheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"slices"
+	"strings"
+)
+
+// fingerprintVulnerabilities sets the fingerprints of the findings of the vulnerabilities found in the target
+// (at the most precise level, sorted and without duplicates)
+func fingerprintVulnerabilities(target Target, report *Report, vulns []*Vulnerability) {
+	for _, v := range vulns {
+		_, findings := getMostPreciseFindings(report.Finding[v.ID])
+		fingerprints := make([]string, 0, len(findings))
+		for _, f := range findings {
+			fingerprints = appendUnique(fingerprints, fingerprint(target, f))
+		}
+		slices.Sort(fingerprints)
+		v.Fingerprints = fingerprints
+	}
+}
+
+// fingerprint returns a deterministic fingerprint of the finding, computed from its OSV ID, the vulnerable module and symbol,
+// and its call site relative to the scanned path (the file and the function of the entry point of the trace).
+// The line and column of the call site are not included, so that the fingerprint does not change
+// when unrelated code is added or removed above the call.
+// example: 5d3c3f5c1a2b9e07
+func fingerprint(target Target, f *Finding) string {
+	vulnerable := f.Trace[0]
+	symbol := vulnerable.Package
+	if vulnerable.Function != "" {
+		symbol = getFunction(vulnerable)
+	}
+	// the call site is the entry point of the trace in the scanned code (if the vulnerable symbols are called),
+	// in the workspace module or the binary in which the vulnerability was found
+	var site []string
+	switch {
+	case target.Binary != "":
+		site = append(site, target.BinaryName())
+	case target.Module != "":
+		site = append(site, target.Module)
+	}
+	if len(f.Trace) > 1 {
+		entry := f.Trace[len(f.Trace)-1]
+		if entry.Position.Filename != "" {
+			site = append(site, path.Join(target.ModuleDir, entry.Position.Filename))
+		}
+		site = append(site, getFunction(entry))
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{f.Osv, vulnerable.Module, symbol, strings.Join(site, " ")}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
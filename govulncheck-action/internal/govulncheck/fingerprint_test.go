@@ -0,0 +1,109 @@
+package govulncheck
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	newFinding := func(file string, line int, caller string) *Finding {
+		return &Finding{
+			Osv:          "GO-2025-3563",
+			FixedVersion: "v1.23.8",
+			Trace: []Trace{
+				{Module: "stdlib", Version: "v1.22.12", Package: "net/http/internal", Function: "Read", Receiver: "*chunkedReader"},
+				{Module: "github.com/codeready-toolchain/host-operator", Package: "github.com/codeready-toolchain/host-operator/pkg/proxy", Function: caller, Position: Position{Filename: file, Line: line, Column: 12}},
+			},
+		}
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		// when
+		first := fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP"))
+		second := fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP"))
+		// then
+		assert.Len(t, first, 16)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("same when the call site moved within its function", func(t *testing.T) {
+		assert.Equal(t,
+			fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP")),
+			fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 57, "ServeHTTP")))
+	})
+
+	t.Run("different for another call site", func(t *testing.T) {
+		fingerprints := []string{
+			fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP")),
+			fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "Start")),
+			fingerprint(Target{}, newFinding("pkg/proxy/handler.go", 42, "ServeHTTP")),
+			// same file in another workspace module
+			fingerprint(Target{Module: "github.com/codeready-toolchain/host-operator/api", ModuleDir: "api"}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP")),
+			// same call in a binary
+			fingerprint(Target{Binary: "bin/host-operator"}, newFinding("", 0, "ServeHTTP")),
+		}
+		assert.Len(t, removeDuplicates(fingerprints), len(fingerprints))
+	})
+
+	t.Run("different for another vulnerable symbol", func(t *testing.T) {
+		// given
+		f := newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP")
+		f.Trace[0].Receiver = ""
+		f.Trace[0].Function = "ParseChunked"
+		// then
+		assert.NotEqual(t, fingerprint(Target{}, newFinding("pkg/proxy/proxy.go", 42, "ServeHTTP")), fingerprint(Target{}, f))
+	})
+
+	t.Run("not called", func(t *testing.T) {
+		// given
+		imported := &Finding{
+			Osv:   "GO-2025-3563",
+			Trace: []Trace{{Module: "stdlib", Version: "v1.22.12", Package: "net/http/internal"}},
+		}
+		required := &Finding{
+			Osv:   "GO-2025-3563",
+			Trace: []Trace{{Module: "stdlib", Version: "v1.22.12"}},
+		}
+		// then
+		assert.NotEqual(t, fingerprint(Target{}, imported), fingerprint(Target{}, required))
+		assert.NotEqual(t, fingerprint(Target{}, imported), fingerprint(Target{Module: "github.com/codeready-toolchain/host-operator/api", ModuleDir: "api"}, imported))
+	})
+}
+
+func TestFingerprintVulnerabilities(t *testing.T) {
+	// given
+	parse := func(t *testing.T) *Report {
+		f, err := os.Open("../testdata/valid_report.json")
+		require.NoError(t, err)
+		defer f.Close()
+		report, err := ParseReport(f)
+		require.NoError(t, err)
+		return report
+	}
+	report := parse(t)
+	// the findings in reverse order
+	reversed := parse(t)
+	for _, findings := range reversed.Finding {
+		slices.Reverse(findings)
+	}
+	vulns := getVulnerabilities(report)
+	reversedVulns := getVulnerabilities(reversed)
+
+	// when
+	fingerprintVulnerabilities(Target{}, report, vulns)
+	fingerprintVulnerabilities(Target{}, reversed, reversedVulns)
+
+	// then
+	require.Len(t, vulns, 2)
+	for i, v := range vulns {
+		assert.NotEmpty(t, v.Fingerprints, v.ID)
+		assert.IsIncreasing(t, v.Fingerprints, v.ID)
+		assert.Equal(t, v.Fingerprints, reversedVulns[i].Fingerprints, v.ID)
+		assert.Equal(t, v.Traces, reversedVulns[i].Traces, v.ID)
+		assert.Equal(t, v.FoundIn, reversedVulns[i].FoundIn, v.ID)
+	}
+}
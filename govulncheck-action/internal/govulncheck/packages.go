@@ -0,0 +1,153 @@
+package govulncheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+)
+
+// ValidateExcludes checks that the given globs are valid patterns
+func ValidateExcludes(excludes []string) error {
+	for _, e := range excludes {
+		if _, err := path.Match(e, ""); err != nil {
+			return fmt.Errorf("invalid exclude: '%s': %w", e, err)
+		}
+	}
+	return nil
+}
+
+// excludePackages lists the packages of the target (in its build context) and returns the import paths of the packages to scan
+// and of the packages which are excluded, ie: whose directory (relative to the root path) or one of its parent directories
+// matches one of the given globs
+func excludePackages(ctx context.Context, root string, target Target, excludes []string) ([]string, []string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get absolute path of '%s': %w", root, err)
+	}
+	output, err := listPackages(ctx, target, "{{.ImportPath}}\t{{.Dir}}")
+	if err != nil {
+		return nil, nil, err
+	}
+	var packages, excluded []string
+	for line := range strings.Lines(output) {
+		importPath, dir, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get relative path of '%s': %w", dir, err)
+		}
+		if isExcluded(filepath.ToSlash(rel), excludes) {
+			excluded = append(excluded, importPath)
+		} else {
+			packages = append(packages, importPath)
+		}
+	}
+	return packages, excluded, nil
+}
+
+// PrintExcludedPackages prints the import paths of the packages which were excluded from the scan
+func PrintExcludedPackages(stdout io.Writer, packages []string) {
+	if len(packages) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout, "Packages excluded from the scan:")
+	for _, p := range packages {
+		fmt.Fprintf(stdout, "  %s\n", p)
+	}
+	fmt.Fprintln(stdout, "")
+}
+
+// isExcluded returns true if the given directory or one of its parent directories matches one of the globs
+func isExcluded(dir string, excludes []string) bool {
+	for d := dir; d != "." && d != "/" && d != ""; d = path.Dir(d) {
+		for _, e := range excludes {
+			if matched, _ := path.Match(e, d); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SourceFiles returns the paths of the Go files of the packages of the target (in its build context), and of the packages
+// of the main modules that they import (eg: the other modules of a Go workspace), ie: the source code which is analyzed
+// at the `package` and `symbol` levels. The test files are included if the tests of the target are analyzed.
+func SourceFiles(ctx context.Context, target Target) ([]string, error) {
+	format := "{{.Dir}}{{range .GoFiles}}\t{{.}}{{end}}{{range .CgoFiles}}\t{{.}}{{end}}"
+	if target.Test {
+		format += "{{range .TestGoFiles}}\t{{.}}{{end}}{{range .XTestGoFiles}}\t{{.}}{{end}}"
+	}
+	output, err := listPackages(ctx, target, "{{if and .Module .Module.Main}}"+format+"{{end}}", "-deps")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for line := range strings.Lines(output) {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		for _, f := range fields[1:] {
+			files = append(files, filepath.Join(fields[0], f))
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// listPackages runs `go list` with the given format on the packages of the target (in its build context)
+func listPackages(ctx context.Context, target Target, format string, flags ...string) (string, error) {
+	args := append([]string{"list", "-f", format}, flags...)
+	if target.Context.Tags != "" {
+		args = append(args, "-tags", target.Context.Tags)
+	}
+	args = append(args, "--")
+	if len(target.Packages) == 0 {
+		args = append(args, "./...")
+	} else {
+		args = append(args, target.Packages...)
+	}
+	output, err := runGo(ctx, target, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to list packages: %w", err)
+	}
+	return output, nil
+}
+
+// BuildList returns the modules of the build list of the target (one per line, with their version and replacement),
+// which depends on the `go.mod` files of all the modules of a Go workspace
+func BuildList(ctx context.Context, target Target) (string, error) {
+	output, err := runGo(ctx, target, "list", "-m", "all")
+	if err != nil {
+		return "", fmt.Errorf("failed to list modules: %w", err)
+	}
+	return output, nil
+}
+
+// runGo runs the `go` command with the given arguments in the directory (and build context) of the target,
+// and returns its output
+func runGo(ctx context.Context, target Target, args ...string) (string, error) {
+	c := exec.CommandContext(ctx, "go", args...)
+	c.Dir = target.Dir
+	if env := target.environ(); len(env) > 0 {
+		c.Env = append(os.Environ(), env...)
+	}
+	stderr := &bytes.Buffer{}
+	c.Stderr = stderr
+	output, err := c.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", failure.New(failure.KindTimeout, ctx.Err())
+		}
+		return "", failure.New(failure.KindBuild, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String())))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,133 @@
+package govulncheck
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludePackages(t *testing.T) {
+
+	// given
+	path := t.TempDir()
+	writeFile(t, filepath.Join(path, "go.mod"), "module example.com/operator\n\ngo 1.26.0\n")
+	writeFile(t, filepath.Join(path, "main.go"), "package main\n\nfunc main() {}\n")
+	writeFile(t, filepath.Join(path, "pkg", "api", "api.go"), "package api\n")
+	writeFile(t, filepath.Join(path, "pkg", "client", "generated", "client.go"), "package generated\n")
+	writeFile(t, filepath.Join(path, "pkg", "client", "generated", "fake", "fake.go"), "package fake\n")
+	writeFile(t, filepath.Join(path, "hack", "tools", "tools.go"), "package tools\n")
+	writeFile(t, filepath.Join(path, "test", "e2e", "e2e.go"), "//go:build e2e\n\npackage e2e\n")
+
+	t.Run("exclude directories and their subdirectories", func(t *testing.T) {
+		// when
+		packages, excluded, err := excludePackages(context.Background(), path, Target{Dir: path}, []string{"hack", "pkg/*/generated"})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/operator", "example.com/operator/pkg/api"}, packages)
+		assert.Equal(t, []string{"example.com/operator/hack/tools", "example.com/operator/pkg/client/generated", "example.com/operator/pkg/client/generated/fake"}, excluded)
+	})
+
+	t.Run("exclude within the given patterns and build context", func(t *testing.T) {
+		// given
+		target := Target{
+			Dir:      path,
+			Packages: []string{"./pkg/...", "./test/..."},
+			Context:  BuildContext{Tags: "e2e"},
+		}
+		// when
+		packages, excluded, err := excludePackages(context.Background(), path, target, []string{"pkg/api"})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/operator/pkg/client/generated", "example.com/operator/pkg/client/generated/fake", "example.com/operator/test/e2e"}, packages)
+		assert.Equal(t, []string{"example.com/operator/pkg/api"}, excluded)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		// when
+		_, _, err := excludePackages(context.Background(), path, Target{Dir: path, Packages: []string{"./unknown"}}, []string{"hack"})
+		// then
+		require.ErrorContains(t, err, "failed to list packages")
+	})
+}
+
+func TestSourceFiles(t *testing.T) {
+
+	// given a workspace in which the operator imports a package of the common module
+	// (and no `-mod=mod` flag in the environment, which is not supported in workspace mode)
+	t.Setenv("GOFLAGS", "")
+	path := t.TempDir()
+	writeFile(t, filepath.Join(path, "go.work"), "go 1.26.0\n\nuse (\n\t./operator\n\t./common\n)\n")
+	writeFile(t, filepath.Join(path, "operator", "go.mod"), "module example.com/operator\n\ngo 1.26.0\n")
+	writeFile(t, filepath.Join(path, "operator", "main.go"), "package main\n\nimport _ \"example.com/common/pkg/log\"\n\nfunc main() {}\n")
+	writeFile(t, filepath.Join(path, "operator", "main_test.go"), "package main\n")
+	writeFile(t, filepath.Join(path, "operator", "test", "e2e", "e2e.go"), "//go:build e2e\n\npackage e2e\n")
+	writeFile(t, filepath.Join(path, "common", "go.mod"), "module example.com/common\n\ngo 1.26.0\n")
+	writeFile(t, filepath.Join(path, "common", "pkg", "log", "log.go"), "package log\n")
+	writeFile(t, filepath.Join(path, "common", "pkg", "client", "client.go"), "package client\n")
+	target := Target{
+		Dir: filepath.Join(path, "operator"),
+		Env: []string{"GOWORK=" + filepath.Join(path, "go.work")},
+	}
+
+	t.Run("packages of the target and imported packages of the workspace", func(t *testing.T) {
+		// when
+		files, err := SourceFiles(context.Background(), target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(path, "common", "pkg", "log", "log.go"),
+			filepath.Join(path, "operator", "main.go"),
+		}, files)
+	})
+
+	t.Run("test files and build context", func(t *testing.T) {
+		// given
+		target := target
+		target.Test = true
+		target.Context = BuildContext{Tags: "e2e"}
+		// when
+		files, err := SourceFiles(context.Background(), target)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(path, "common", "pkg", "log", "log.go"),
+			filepath.Join(path, "operator", "main.go"),
+			filepath.Join(path, "operator", "main_test.go"),
+			filepath.Join(path, "operator", "test", "e2e", "e2e.go"),
+		}, files)
+	})
+}
+
+func TestPrintExcludedPackages(t *testing.T) {
+
+	t.Run("excluded packages", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintExcludedPackages(&buf, []string{"example.com/operator/hack/tools", "example.com/operator/pkg/client/generated"})
+		// then
+		assert.Equal(t, `Packages excluded from the scan:
+  example.com/operator/hack/tools
+  example.com/operator/pkg/client/generated
+
+`, buf.String())
+	})
+
+	t.Run("no excluded package", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintExcludedPackages(&buf, nil)
+		// then
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestValidateExcludes(t *testing.T) {
+	require.NoError(t, ValidateExcludes([]string{"hack", "pkg/*/generated", "zz_*"}))
+	require.EqualError(t, ValidateExcludes([]string{"hack", "pkg/[generated"}), "invalid exclude: 'pkg/[generated': syntax error in pattern")
+}
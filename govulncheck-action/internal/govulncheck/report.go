@@ -1,13 +1,31 @@
 package govulncheck
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
 )
 
-// hasVulnerability determines if the finding reports a vulnerability
-// we can know it by checking if function field is present
+const (
+	// ScanLevelModule is the level at which the vulnerable modules are required
+	ScanLevelModule = "module"
+	// ScanLevelPackage is the level at which the vulnerable packages are imported
+	ScanLevelPackage = "package"
+	// ScanLevelSymbol is the level at which the vulnerable symbols are called
+	ScanLevelSymbol = "symbol"
+)
+
+// scanLevels are the scan levels, from the least to the most precise
+var scanLevels = []string{ScanLevelModule, ScanLevelPackage, ScanLevelSymbol}
+
+// getLevel determines the level of the finding
+// we can know it by checking which fields are present in the first item of the trace:
+// - symbol: the function field is present (the vulnerable symbol is called)
+// - package: the package field is present (the vulnerable package is imported)
+// - module: only the module field is present (the vulnerable module is required)
 // example:
 //
 //	"finding": {
@@ -26,62 +44,145 @@ import (
 //	          "column": 32
 //	        }
 //	      },
-func hasVulnerability(trace []Trace) bool {
+func getLevel(trace []Trace) string {
 	for _, t := range trace {
 		if t.Function != "" {
-			return true
+			return ScanLevelSymbol
+		}
+	}
+	for _, t := range trace {
+		if t.Package != "" {
+			return ScanLevelPackage
 		}
 	}
-	return false
+	return ScanLevelModule
+}
+
+// isLevelBelow returns true if the given level is less precise than the other one
+func isLevelBelow(level, other string) bool {
+	return slices.Index(scanLevels, level) < slices.Index(scanLevels, other)
+}
+
+// ParseReport reads the messages of a govulncheck JSON report until the end of the stream
+func ParseReport(r io.Reader) (*Report, error) {
+	return parseReport(r, nil)
 }
 
-func parseReport(rawReport []byte) (*Report, error) {
+// parseReport decodes the messages of a govulncheck JSON report as they are read,
+// and calls the given function (if any) for each progress message
+func parseReport(r io.Reader, onProgress func(*Progress)) (*Report, error) {
 	report := &Report{
 		Finding: make(map[string][]*Finding),
 		OSV:     make(map[string]*OSV),
 	}
-
-	decoder := json.NewDecoder(bytes.NewReader(rawReport))
-
+	decoder := json.NewDecoder(r)
 	for {
-		var obj map[string]interface{}
-
-		if err := decoder.Decode(&obj); err != nil {
-			if err.Error() == "EOF" {
-				// stop the loop when we get into the end of the file
-				break
-			}
+		var msg Message
+		if err := decoder.Decode(&msg); errors.Is(err, io.EOF) {
+			return report, nil
+		} else if err != nil {
 			return nil, fmt.Errorf("error decoding JSON: %w", err)
 		}
-
-		// save all findings (results)
-		if finding, ok := obj["finding"]; ok {
-			var f Finding
-			findingBytes, err := json.Marshal(finding)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal to Finding struct: %w", err)
-			}
-			if err := json.Unmarshal(findingBytes, &f); err != nil {
-				return &Report{}, fmt.Errorf("failed to unmarshal Finding struct: %w", err)
-			}
-			if hasVulnerability(f.Trace) {
-				report.Finding[f.Osv] = append(report.Finding[f.Osv], &f)
+		switch {
+		case msg.Config != nil:
+			report.Config = msg.Config
+		case msg.Progress != nil:
+			report.Progress = append(report.Progress, msg.Progress)
+			if onProgress != nil {
+				onProgress(msg.Progress)
 			}
+		case msg.OSV != nil:
+			// all osv entries (rules)
+			report.OSV[msg.OSV.ID] = msg.OSV
+		case msg.Finding != nil:
+			// all findings (results)
+			report.Finding[msg.Finding.Osv] = append(report.Finding[msg.Finding.Osv], msg.Finding)
+		}
+	}
+}
 
-			// save all osv entries (rules)
-		} else if osv, ok := obj["osv"]; ok {
-			var o OSV
-			osvBytes, err := json.Marshal(osv)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal to OSV struct: %w", err)
-			}
-			if err := json.Unmarshal(osvBytes, &o); err != nil {
-				return &Report{}, fmt.Errorf("failed to unmarshal OSV struct: %w", err)
-			}
-			report.OSV[o.ID] = &o
+// Encode writes the report as a stream of govulncheck JSON messages, which can be read with ParseReport
+// (the OSV entries and the findings are sorted by ID)
+func (r *Report) Encode(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	var msgs []Message
+	if r.Config != nil {
+		msgs = append(msgs, Message{Config: r.Config})
+	}
+	for _, p := range r.Progress {
+		msgs = append(msgs, Message{Progress: p})
+	}
+	for _, id := range slices.Sorted(maps.Keys(r.OSV)) {
+		msgs = append(msgs, Message{OSV: r.OSV[id]})
+	}
+	for _, id := range slices.Sorted(maps.Keys(r.Finding)) {
+		for _, f := range r.Finding[id] {
+			msgs = append(msgs, Message{Finding: f})
 		}
+	}
+	for _, msg := range msgs {
+		if err := encoder.Encode(msg); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	}
+	return nil
+}
+
+// Diagnostic is a problem found in a govulncheck report, which would prevent its findings from being processed
+type Diagnostic struct {
+	// OSV is the ID of the vulnerability of the finding (empty if unknown)
+	OSV string
+	// Message describes the problem
+	Message string
+}
 
+func (d Diagnostic) String() string {
+	if d.OSV == "" {
+		return d.Message
 	}
+	return fmt.Sprintf("%s: %s", d.OSV, d.Message)
+}
 
-	return report, nil
+// Validate checks the findings of the report and returns a diagnostic for each problem:
+// - the findings without an OSV ID, without a trace, or without a module in the first item of their trace are removed,
+// - an OSV entry with the ID only is added for the findings whose OSV entry is missing, so that they are still reported.
+func (r *Report) Validate() []Diagnostic {
+	if r == nil {
+		return nil
+	}
+	var diagnostics []Diagnostic
+	for _, id := range slices.Sorted(maps.Keys(r.Finding)) {
+		findings := r.Finding[id]
+		valid := make([]*Finding, 0, len(findings))
+		for _, f := range findings {
+			switch {
+			case id == "":
+				diagnostics = append(diagnostics, Diagnostic{Message: "finding without OSV ID"})
+			case len(f.Trace) == 0:
+				diagnostics = append(diagnostics, Diagnostic{OSV: id, Message: "finding without trace"})
+			case f.Trace[0].Module == "":
+				diagnostics = append(diagnostics, Diagnostic{OSV: id, Message: "finding without module in its trace"})
+			default:
+				valid = append(valid, f)
+			}
+		}
+		if len(valid) == 0 {
+			delete(r.Finding, id)
+			continue
+		}
+		r.Finding[id] = valid
+		if r.OSV[id] == nil {
+			diagnostics = append(diagnostics, Diagnostic{OSV: id, Message: "no OSV entry for the finding"})
+			if r.OSV == nil {
+				r.OSV = make(map[string]*OSV)
+			}
+			r.OSV[id] = &OSV{
+				ID: id,
+				DatabaseSpecific: DatabaseSpecific{
+					URL: "https://pkg.go.dev/vuln/" + id,
+				},
+			}
+		}
+	}
+	return diagnostics
 }
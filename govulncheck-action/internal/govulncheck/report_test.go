@@ -1,8 +1,11 @@
 package govulncheck
 
 import (
+	"bytes"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,32 +13,79 @@ import (
 
 func TestParseReport(t *testing.T) {
 	t.Run("valid report", func(t *testing.T) {
+		published := time.Date(2025, 4, 8, 19, 46, 23, 0, time.UTC)
 		expectedOSVs := map[string]*OSV{
 			"GO-2024-2611": {
 				ID:      "GO-2024-2611",
 				Summary: "Infinite loop in JSON unmarshaling in google.golang.org/protobuf",
 				DatabaseSpecific: DatabaseSpecific{
-					URL: "https://pkg.go.dev/vuln/GO-2024-2611",
+					URL:          "https://pkg.go.dev/vuln/GO-2024-2611",
+					ReviewStatus: ReviewStatusReviewed,
 				},
 			},
 			"GO-2025-3563": {
-				ID:      "GO-2025-3563",
-				Summary: "Request smuggling due to acceptance of invalid chunked data in net/http",
+				ID:        "GO-2025-3563",
+				Modified:  &published,
+				Published: &published,
+				Aliases:   []string{"CVE-2025-22871"},
+				Summary:   "Request smuggling due to acceptance of invalid chunked data in net/http",
+				Affected: []OSVAffected{
+					{
+						Module: OSVModule{Path: "stdlib", Ecosystem: "Go"},
+						Ranges: []Range{
+							{
+								Type: "SEMVER",
+								Events: []RangeEvent{
+									{Introduced: "0"},
+									{Fixed: "1.23.8"},
+									{Introduced: "1.24.0-0"},
+									{Fixed: "1.24.2"},
+								},
+							},
+						},
+					},
+				},
 				DatabaseSpecific: DatabaseSpecific{
-					URL: "https://pkg.go.dev/vuln/GO-2025-3563",
+					URL:          "https://pkg.go.dev/vuln/GO-2025-3563",
+					ReviewStatus: ReviewStatusReviewed,
 				},
 			},
 			"GO-2025-3547": {
 				ID:      "GO-2025-3547",
 				Summary: "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
 				DatabaseSpecific: DatabaseSpecific{
-					URL: "https://pkg.go.dev/vuln/GO-2025-3547",
+					URL:          "https://pkg.go.dev/vuln/GO-2025-3547",
+					ReviewStatus: ReviewStatusUnreviewed,
 				},
 			},
 		}
 
 		expectedFindings := map[string][]*Finding{
 			"GO-2025-3563": {
+				// module level
+				{
+					Osv:          "GO-2025-3563",
+					FixedVersion: "v1.23.8",
+					Trace: []Trace{
+						{
+							Module:  "stdlib",
+							Version: "v1.22.12",
+						},
+					},
+				},
+				// package level
+				{
+					Osv:          "GO-2025-3563",
+					FixedVersion: "v1.23.8",
+					Trace: []Trace{
+						{
+							Module:  "stdlib",
+							Version: "v1.22.12",
+							Package: "net/http/internal",
+						},
+					},
+				},
+				// symbol level
 				{
 					Osv:          "GO-2025-3563",
 					FixedVersion: "v1.23.8",
@@ -45,6 +95,7 @@ func TestParseReport(t *testing.T) {
 							Version:  "v1.22.12",
 							Package:  "net/http/internal",
 							Function: "Read",
+							Receiver: "*chunkedReader",
 							Position: Position{
 								Filename: "src/net/http/internal/chunked.go",
 								Line:     97,
@@ -56,6 +107,7 @@ func TestParseReport(t *testing.T) {
 							Version:  "v1.22.12",
 							Package:  "net/http",
 							Function: "readLocked",
+							Receiver: "*body",
 							Position: Position{
 								Filename: "src/net/http/transfer.go",
 								Line:     840,
@@ -76,6 +128,28 @@ func TestParseReport(t *testing.T) {
 				},
 			},
 			"GO-2025-3547": {
+				// module level
+				{
+					Osv: "GO-2025-3547",
+					Trace: []Trace{
+						{
+							Module:  "k8s.io/kubernetes",
+							Version: "v1.30.10",
+						},
+					},
+				},
+				// package level
+				{
+					Osv: "GO-2025-3547",
+					Trace: []Trace{
+						{
+							Module:  "k8s.io/kubernetes",
+							Version: "v1.30.10",
+							Package: "k8s.io/kubernetes/pkg/kubelet/cri/remote",
+						},
+					},
+				},
+				// symbol level
 				{
 					Osv: "GO-2025-3547",
 					Trace: []Trace{
@@ -110,6 +184,7 @@ func TestParseReport(t *testing.T) {
 							Version:  "v1.30.10",
 							Package:  "k8s.io/kubernetes/pkg/kubelet/cri/remote",
 							Function: "ContainerStatus",
+							Receiver: "*remoteRuntimeService",
 							Position: Position{
 								Filename: "pkg/kubelet/cri/remote/remote_runtime.go",
 								Line:     416,
@@ -132,30 +207,83 @@ func TestParseReport(t *testing.T) {
 		}
 
 		// given
-		report, err := os.ReadFile("../testdata/valid_report.json")
+		f, err := os.Open("../testdata/valid_report.json")
 		require.NoError(t, err)
+		defer f.Close()
 		// when
-		parsedReport, err := parseReport(report)
+		parsedReport, err := ParseReport(f)
 		require.NoError(t, err)
 		// then
+		lastModified := time.Date(2025, 4, 24, 18, 14, 57, 0, time.UTC)
+		assert.Equal(t, &Config{
+			ProtocolVersion: "v1.0.0",
+			ScannerName:     "govulncheck",
+			ScannerVersion:  "v1.1.4",
+			DB:              "https://vuln.go.dev",
+			DBLastModified:  &lastModified,
+			GoVersion:       "go1.22.12",
+			ScanLevel:       "symbol",
+			ScanMode:        "source",
+		}, parsedReport.Config)
+		assert.Empty(t, parsedReport.Progress)
 		assert.Len(t, parsedReport.Finding, 2)
 		assert.Equal(t, expectedFindings, parsedReport.Finding)
 		assert.Len(t, parsedReport.OSV, 3)
 		assert.Equal(t, expectedOSVs, parsedReport.OSV)
 	})
 
+	t.Run("progress messages", func(t *testing.T) {
+		// given
+		report := `{"progress":{"message":"Scanning your code and 42 packages across 7 dependent modules for known vulnerabilities..."}}
+{"progress":{"message":"Fetching vulnerabilities from the database..."}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12"}]}}`
+		var progress []string
+		// when
+		parsedReport, err := parseReport(strings.NewReader(report), func(p *Progress) {
+			progress = append(progress, p.Message)
+		})
+		// then
+		require.NoError(t, err)
+		expected := []string{
+			"Scanning your code and 42 packages across 7 dependent modules for known vulnerabilities...",
+			"Fetching vulnerabilities from the database...",
+		}
+		assert.Equal(t, expected, progress)
+		require.Len(t, parsedReport.Progress, 2)
+		assert.Equal(t, expected[1], parsedReport.Progress[1].Message)
+		assert.Nil(t, parsedReport.Config)
+		assert.Len(t, parsedReport.Finding["GO-2025-3563"], 1)
+	})
+
+	t.Run("encoded report can be parsed", func(t *testing.T) {
+		// given
+		f, err := os.Open("../testdata/valid_report.json")
+		require.NoError(t, err)
+		defer f.Close()
+		report, err := ParseReport(f)
+		require.NoError(t, err)
+		buf := &bytes.Buffer{}
+		// when
+		err = report.Encode(buf)
+		// then
+		require.NoError(t, err)
+		parsedReport, err := ParseReport(buf)
+		require.NoError(t, err)
+		assert.Equal(t, report, parsedReport)
+	})
+
 	t.Run("invalid report - error decoding JSON", func(t *testing.T) {
 		// given
-		report := []byte(`{`)
+		report := `{`
 		// when
-		_, err := parseReport(report)
+		_, err := ParseReport(strings.NewReader(report))
 		// then
 		require.EqualError(t, err, "error decoding JSON: unexpected EOF")
 	})
 
 	t.Run("invalid report - failed to unmarshal Finding struct", func(t *testing.T) {
 		// given
-		report := []byte(`
+		report := `
 {
   "finding": {
     "osv": "GO-2025-3563",
@@ -168,10 +296,104 @@ func TestParseReport(t *testing.T) {
     ]
   }
 }
-`)
+`
+		// when
+		_, err := ParseReport(strings.NewReader(report))
+		// then
+		require.ErrorContains(t, err, "error decoding JSON: ")
+		require.ErrorContains(t, err, "cannot unmarshal number")
+	})
+}
+
+func TestValidateReport(t *testing.T) {
+	// given
+	report, err := ParseReport(strings.NewReader(`{"osv":{"id":"GO-2025-3563","summary":"Request smuggling"}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12"}]}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[]}}
+{"finding":{"osv":"GO-2025-3547"}}
+{"finding":{"osv":"GO-2024-2611","fixed_version":"v1.33.0","trace":[{"module":"google.golang.org/protobuf","version":"v1.32.0"}]}}
+{"finding":{"osv":"GO-2025-0001","trace":[{"version":"v1.0.0"}]}}
+{"finding":{"fixed_version":"v1.0.1","trace":[{"module":"example.com/lib","version":"v1.0.0"}]}}`))
+	require.NoError(t, err)
+
+	// when
+	diagnostics := report.Validate()
+
+	// then
+	assert.Equal(t, []Diagnostic{
+		{Message: "finding without OSV ID"},
+		{OSV: "GO-2024-2611", Message: "no OSV entry for the finding"},
+		{OSV: "GO-2025-0001", Message: "finding without module in its trace"},
+		{OSV: "GO-2025-3547", Message: "finding without trace"},
+		{OSV: "GO-2025-3563", Message: "finding without trace"},
+	}, diagnostics)
+	assert.Equal(t, "GO-2025-3547: finding without trace", diagnostics[3].String())
+	// only the valid findings are kept
+	assert.Len(t, report.Finding, 2)
+	assert.Len(t, report.Finding["GO-2025-3563"], 1)
+	assert.Len(t, report.Finding["GO-2024-2611"], 1)
+	// with an OSV entry
+	assert.Equal(t, &OSV{
+		ID:               "GO-2024-2611",
+		DatabaseSpecific: DatabaseSpecific{URL: "https://pkg.go.dev/vuln/GO-2024-2611"},
+	}, report.OSV["GO-2024-2611"])
+	// which can be processed
+	vulns := getVulnerabilities(report)
+	require.Len(t, vulns, 2)
+
+	t.Run("valid report", func(t *testing.T) {
+		// given
+		f, err := os.Open("../testdata/valid_report.json")
+		require.NoError(t, err)
+		defer f.Close()
+		report, err := ParseReport(f)
+		require.NoError(t, err)
+		// when
+		diagnostics := report.Validate()
+		// then
+		assert.Empty(t, diagnostics)
+		assert.Len(t, report.Finding, 2)
+	})
+
+	t.Run("nil report", func(t *testing.T) {
 		// when
-		_, err := parseReport(report)
+		var report *Report
 		// then
-		require.EqualError(t, err, "failed to unmarshal Finding struct: json: cannot unmarshal number into Go struct field Trace.trace.version of type string")
+		assert.Empty(t, report.Validate())
 	})
 }
+
+func TestGetLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		trace    []Trace
+		expected string
+	}{
+		{
+			name:     "module level",
+			trace:    []Trace{{Module: "stdlib", Version: "v1.22.12"}},
+			expected: ScanLevelModule,
+		},
+		{
+			name:     "package level",
+			trace:    []Trace{{Module: "stdlib", Version: "v1.22.12", Package: "net/http/internal"}},
+			expected: ScanLevelPackage,
+		},
+		{
+			name: "symbol level",
+			trace: []Trace{
+				{Module: "stdlib", Version: "v1.22.12", Package: "net/http/internal", Function: "Read"},
+				{Module: "package", Package: "package/pkg/configuration", Function: "Load"},
+			},
+			expected: ScanLevelSymbol,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// when
+			level := getLevel(test.trace)
+			// then
+			assert.Equal(t, test.expected, level)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package govulncheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Results are the results of a scan, as written in the JSON output file
+// (eg: to be used as the baseline of the scans of the pull requests)
+type Results struct {
+	// Vulnerabilities are the vulnerabilities which are not ignored, including the informational ones
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+	// ExcludedPackages are the import paths of the packages which were excluded from the scan
+	ExcludedPackages []string `json:"excluded_packages,omitempty"`
+}
+
+// WriteResults writes the vulnerabilities and the packages excluded from the scan in the JSON output file
+func WriteResults(path string, vulns []*Vulnerability, excludedPackages []string) error {
+	if vulns == nil {
+		vulns = []*Vulnerability{}
+	}
+	data, err := json.MarshalIndent(Results{Vulnerabilities: vulns, ExcludedPackages: excludedPackages}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// ReadResults reads the vulnerabilities of a JSON output file
+// (an empty, non-nil slice if the scan did not find any vulnerability)
+func ReadResults(path string) ([]*Vulnerability, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	results := Results{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal results '%s': %w", path, err)
+	}
+	if results.Vulnerabilities == nil {
+		return []*Vulnerability{}, nil
+	}
+	return results.Vulnerabilities, nil
+}
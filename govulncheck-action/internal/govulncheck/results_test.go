@@ -0,0 +1,101 @@
+package govulncheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResults(t *testing.T) {
+
+	t.Run("round trip", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "results.json")
+		vulns := []*Vulnerability{
+			{
+				ID:           "GO-2025-3563",
+				Summary:      "Request smuggling due to acceptance of invalid chunked data in net/http",
+				MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3563",
+				Module:       "stdlib",
+				Affected:     []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+				Aliases:      []string{"CVE-2025-22871"},
+				Published:    time.Date(2025, 4, 8, 19, 46, 23, 0, time.UTC),
+				ReviewStatus: ReviewStatusReviewed,
+				Severity:     &severity.Severity{Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", Score: 7.5},
+				CallStacks: []CallStack{{Frames: []Frame{
+					{Module: "github.com/codeready-toolchain/host-operator", Function: "github.com/codeready-toolchain/host-operator/pkg/proxy.ServeHTTP", Position: "pkg/proxy/proxy.go:42:12"},
+					{Module: "stdlib", Function: "net/http/internal.(*chunkedReader).Read"},
+				}}},
+				Fingerprints: []string{"9133e961351bef29"},
+				Level:        ScanLevelSymbol,
+			},
+			{
+				ID:            "GO-2025-3547",
+				Summary:       "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+				MoreInfo:      "https://pkg.go.dev/vuln/GO-2025-3547",
+				Module:        "k8s.io/kubernetes",
+				Affected:      []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}},
+				Fingerprints:  []string{"a6ecd0d33867e23c"},
+				Level:         ScanLevelPackage,
+				Informational: "the vulnerable package is imported but the vulnerable symbols are not called",
+			},
+		}
+		// when
+		require.NoError(t, WriteResults(path, vulns, nil))
+		results, err := ReadResults(path)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, vulns, results)
+	})
+
+	t.Run("excluded packages", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "results.json")
+		// when
+		require.NoError(t, WriteResults(path, nil, []string{"example.com/operator/hack/tools", "example.com/operator/pkg/client/generated"}))
+		// then
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"vulnerabilities": [],
+			"excluded_packages": ["example.com/operator/hack/tools", "example.com/operator/pkg/client/generated"]
+		}`, string(data))
+	})
+
+	t.Run("no vulnerabilities", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "results.json")
+		// when
+		require.NoError(t, WriteResults(path, nil, nil))
+		results, err := ReadResults(path)
+		// then
+		require.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Empty(t, results)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"vulnerabilities": []}`, string(data))
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "results.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"vulnerabilities": {}}`), 0o600))
+		// when
+		_, err := ReadResults(path)
+		// then
+		require.ErrorContains(t, err, "failed to unmarshal results")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		// when
+		_, err := ReadResults(filepath.Join(t.TempDir(), "results.json"))
+		// then
+		require.ErrorContains(t, err, "failed to read results")
+	})
+}
@@ -1,59 +1,433 @@
 package govulncheck
 
 import (
-	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/vuln/scan"
 )
 
-func Scan(ctx context.Context, logger *slog.Logger, scan ScanFunc, path string, config configuration.Configuration) ([]*Vulnerability, []*configuration.Vulnerability, error) {
-	rawReport, err := scan(ctx, logger, path)
+const (
+	// ModeSource is the mode in which the source code of the Go modules is scanned
+	ModeSource = "source"
+	// ModeBinary is the mode in which compiled Go binaries are scanned
+	ModeBinary = "binary"
+)
+
+// Options are the options of a scan
+type Options struct {
+	// Path is the path to the repository root directory to scan
+	Path string
+	// Mode is the scan mode, either `source` (default) or `binary`
+	Mode string
+	// Binaries are the paths to the binaries to scan in `binary` mode
+	Binaries []string
+	// ImageArchives are the paths to the image archives (`docker save` or OCI layout tarballs, or OCI layout directories)
+	// whose Go binaries are scanned in `binary` mode
+	ImageArchives []string
+	// ScanLevel is the level of the analysis (`module`, `package` or `symbol`, the latter being the default)
+	ScanLevel string
+	// DB is the URL of the vulnerability database (or empty for the default database)
+	DB string
+	// Platforms are the target platforms (in the `<os>/<arch>` format) in which the source code is analyzed
+	// (the default platform if empty)
+	Platforms []string
+	// Tags are the sets of build tags (each item being a comma-separated list of tags) with which the source code is analyzed
+	// (no build tags if empty)
+	Tags []string
+	// IncludeTests is true if the test files are analyzed (only in `source` mode)
+	IncludeTests bool
+	// FailTestOnly is true if the vulnerabilities which are only reachable from test code must fail the scan,
+	// otherwise they are only informational
+	FailTestOnly bool
+	// FailUnreachable is true if the vulnerabilities found at a less precise level than the scan level must fail the scan,
+	// otherwise they are only informational
+	FailUnreachable bool
+	// UnreviewedWarnDays is the number of days after their publication during which the vulnerabilities
+	// of unreviewed advisories are only informational (always failing the scan if not positive)
+	UnreviewedWarnDays int
+	// SeverityData contains the severities of the vulnerabilities, keyed by alias (no severities if nil).
+	// The vulnerabilities are sorted by decreasing score when it is specified.
+	SeverityData severity.Data
+	// MinSeverity is the minimum score of the vulnerabilities which fail the scan (the others being informational).
+	// The vulnerabilities whose severity is unknown always fail the scan.
+	MinSeverity float64
+	// FailOn are the selectors of the vulnerabilities which fail the scan (eg: `third-party` and `fixable`),
+	// the others being informational (all the vulnerabilities fail the scan if empty)
+	FailOn []string
+	// Baseline contains the vulnerabilities of a previous scan (eg: of the target branch of a pull request),
+	// which are only informational if they have no new findings (no baseline if nil)
+	Baseline []*Vulnerability
+	// Packages are the patterns of the packages to scan in `source` mode (`./...` if empty)
+	Packages []string
+	// Exclude are the globs of the directories (relative to the path) of the packages to exclude from the scan in `source` mode.
+	// A package is excluded if its directory or one of its parent directories matches a glob.
+	Exclude []string
+	// FromReport is true if the scan function returns a report of a previous scan instead of running govulncheck,
+	// in which case there is a single target, without any build context, package pattern or vendored dependencies
+	FromReport bool
+	// Env contains the additional environment variables to set when scanning the targets (eg: `GOTOOLCHAIN`)
+	Env []string
+	// StrictReport is true if the scan must fail when a govulncheck report contains invalid findings,
+	// otherwise they are skipped with a warning
+	StrictReport bool
+	// Parallelism is the maximum number of targets scanned concurrently (sequential scans if lower than 2)
+	Parallelism int
+}
+
+// Scan scans the targets specified in the options, and returns the vulnerabilities which are not ignored in the configuration,
+// along with the ignored vulnerabilities which are outdated, and the import paths of the packages excluded from the scan.
+// If the scan times out or is cancelled, the vulnerabilities found in the targets which were completely scanned
+// are returned along with the error (but no outdated vulnerabilities, since the report is partial).
+func Scan(ctx context.Context, logger *slog.Logger, scan ScanFunc, opts Options, config configuration.Configuration) ([]*Vulnerability, []*configuration.Vulnerability, []string, error) {
+	targets, err := getTargets(opts)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, failure.New(failure.KindBuild, err)
+	}
+	if opts.Mode == ModeBinary && len(opts.ImageArchives) > 0 {
+		dir, err := os.MkdirTemp("", "govulncheck-images-")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		imageTargets, err := getImageTargets(logger, opts.ImageArchives, dir)
+		if err != nil {
+			return nil, nil, nil, failure.New(failure.KindConfig, err)
+		}
+		targets = append(targets, imageTargets...)
 	}
-	// get the vulns from the report
-	vulns, err := getVulnerabilities(rawReport)
+	contexts, err := getBuildContexts(opts.Platforms, opts.Tags)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, failure.New(failure.KindConfig, err)
+	}
+	targets = withBuildContexts(targets, contexts)
+	// in a Go workspace, the dependencies are vendored in the directory of the `go.work` file
+	var vendorDir string
+	var vendored map[string]string
+	if opts.Mode != ModeBinary && !opts.FromReport {
+		if vendorDir, err = VendorDir(opts.Path); err != nil {
+			return nil, nil, nil, failure.New(failure.KindBuild, err)
+		}
+		if vendorDir != "" {
+			if vendored, err = readVendoredModules(vendorDir); err != nil {
+				return nil, nil, nil, failure.New(failure.KindBuild, err)
+			}
+			logger.Info("scanning the vendored dependencies", "dir", vendorDir, "modules", len(vendored))
+		}
+	}
+	for i := range targets {
+		// settings which are common to all targets
+		targets[i].ScanLevel = opts.ScanLevel
+		targets[i].DB = opts.DB
+		targets[i].Test = opts.IncludeTests
+		targets[i].Packages = opts.Packages
+		targets[i].VendorDir = vendorDir
+		targets[i].Env = slices.Concat(targets[i].Env, opts.Env)
+	}
+	// results are stored by target index, so that they are merged in a deterministic order
+	// regardless of the order in which the scans complete
+	results := make([][]*Vulnerability, len(targets))
+	completed := make([]bool, len(targets))
+	excludedPackages := make([][]string, len(targets))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(opts.Parallelism, 1))
+	for i, target := range targets {
+		g.Go(func() error {
+			targetLogger := newTargetLogger(logger, target)
+			if len(opts.Exclude) > 0 {
+				packages, excluded, err := excludePackages(gctx, opts.Path, target, opts.Exclude)
+				if err != nil {
+					return err
+				}
+				if len(excluded) > 0 {
+					targetLogger.Info("packages excluded from the scan", "packages", excluded)
+				}
+				excludedPackages[i] = excluded
+				if len(packages) == 0 {
+					targetLogger.Warn("all packages are excluded from the scan")
+					completed[i] = true
+					return nil
+				}
+				target.Packages = packages
+			}
+			report, err := scanTarget(gctx, targetLogger, scan, target, opts.StrictReport)
+			if err != nil {
+				return err
+			}
+			// get the vulns from the report
+			vulns := getVulnerabilities(report)
+			if target.Test {
+				// govulncheck only reports the shortest call stack of each vulnerable symbol, which may start in the test code
+				// even if the symbol is also called from the production code: the target is also scanned without its test files,
+				// and only the vulnerabilities which are not called from the production code are reachable from test code only
+				production := target
+				production.Test = false
+				productionReport, err := scanTarget(gctx, targetLogger.With("tests", false), scan, production, opts.StrictReport)
+				if err != nil {
+					return err
+				}
+				attributeTestOnly(vulns, getVulnerabilities(productionReport))
+			}
+			attributeVulnerabilities(target, vulns)
+			fingerprintVulnerabilities(target, report, vulns)
+			if target.VendorDir != "" {
+				attributeVendoredModules(vulns, vendored)
+			}
+			results[i] = vulns
+			completed[i] = true
+			return nil
+		})
+	}
+	err = g.Wait()
+	// the same packages are excluded in all the build contexts of a module
+	excluded := slices.Compact(slices.Sorted(slices.Values(slices.Concat(excludedPackages...))))
+	if err != nil {
+		if failure.KindOf(err) != failure.KindTimeout {
+			return nil, nil, nil, err
+		}
+		// partial report with the targets which were completely scanned
+		scanned := 0
+		for i := range targets {
+			if completed[i] {
+				scanned++
+			}
+		}
+		logger.Warn("scan interrupted", "scanned", scanned, "total", len(targets), "error", err.Error())
+		vulns := classifyVulns(mergeVulnerabilities(results...), opts)
+		return pruneIgnoredVulns(logger, vulns, config.IgnoredVulnerabilities), nil, excluded, err
 	}
+	vulns := classifyVulns(mergeVulnerabilities(results...), opts)
 
 	// remove ignored vulnerabilities
-	return pruneIgnoredVulns(logger, vulns, config.IgnoredVulnerabilities), listOutdatedVulns(vulns, config.IgnoredVulnerabilities), nil
+	return pruneIgnoredVulns(logger, vulns, config.IgnoredVulnerabilities), listOutdatedVulns(vulns, config.IgnoredVulnerabilities, cmp.Or(opts.ScanLevel, ScanLevelSymbol), opts.FailUnreachable), excluded, nil
 }
 
-type ScanFunc func(ctx context.Context, logger *slog.Logger, path string) ([]byte, error)
+// scanTarget scans the target, and logs the configuration of the scan and the invalid findings of the report
+func scanTarget(ctx context.Context, logger *slog.Logger, scan ScanFunc, target Target, strict bool) (*Report, error) {
+	report, err := scan(ctx, logger, target)
+	if err != nil {
+		return nil, err
+	}
+	if report != nil && report.Config != nil {
+		logConfig(logger, report.Config)
+	}
+	if err := validateReport(logger, report, strict); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// newTargetLogger returns a logger with the module or binary of the target, and its build context
+func newTargetLogger(logger *slog.Logger, target Target) *slog.Logger {
+	if target.Module != "" {
+		logger = logger.With("module", target.Module)
+	} else if target.Binary != "" {
+		logger = logger.With("binary", target.BinaryName())
+	}
+	if c := target.Context.String(); c != "" {
+		logger = logger.With("context", c)
+	}
+	return logger
+}
+
+// validateReport checks the findings of the report, and returns an error if some of them are invalid in strict mode,
+// otherwise it logs a warning for each invalid finding (which is then skipped)
+func validateReport(logger *slog.Logger, report *Report, strict bool) error {
+	diagnostics := report.Validate()
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("invalid govulncheck report: %s", joinDiagnostics(diagnostics))
+	}
+	for _, d := range diagnostics {
+		logger.Warn("invalid finding in the govulncheck report", "osv", d.OSV, "problem", d.Message)
+	}
+	return nil
+}
+
+// classifyVulns enriches the merged vulnerabilities with their severity, and marks the ones which must not fail the scan
+// as informational
+func classifyVulns(vulns []*Vulnerability, opts Options) []*Vulnerability {
+	enrichSeverity(vulns, opts.SeverityData)
+	classifyUnreachableVulns(vulns, cmp.Or(opts.ScanLevel, ScanLevelSymbol), opts.FailUnreachable)
+	classifyTestOnlyVulns(vulns, opts.FailTestOnly)
+	classifyUnreviewedVulns(vulns, opts.UnreviewedWarnDays)
+	classifyMinSeverityVulns(vulns, opts.MinSeverity)
+	classifyFailOnVulns(vulns, opts.FailOn)
+	classifyBaselineVulns(vulns, opts.Baseline)
+	return vulns
+}
 
+// ScanFunc scans the given target and returns the govulncheck report (or nil if there is nothing to report)
+type ScanFunc func(ctx context.Context, logger *slog.Logger, target Target) (*Report, error)
+
+// ReportScan returns a ScanFunc which returns the govulncheck JSON report in the given file (or in stdin if the path is `-`)
+// instead of running govulncheck
+func ReportScan(path string, stdin io.Reader) ScanFunc {
+	return func(_ context.Context, logger *slog.Logger, _ Target) (*Report, error) {
+		r := stdin
+		if path == "-" {
+			logger.Info("reading govulncheck report from stdin")
+		} else {
+			logger.Info("reading govulncheck report", "path", path)
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, failure.New(failure.KindConfig, fmt.Errorf("failed to read report: %w", err))
+			}
+			defer f.Close()
+			r = f
+		}
+		report, err := ParseReport(r)
+		if err != nil {
+			return nil, failure.New(failure.KindConfig, fmt.Errorf("failed to parse report: %w", err))
+		}
+		return report, nil
+	}
+}
+
+// DefaultScan returns a ScanFunc which runs govulncheck on the target.
+// The output of govulncheck is decoded while it is running, so that it is never buffered entirely.
 func DefaultScan(stderr io.Writer) ScanFunc {
-	return func(ctx context.Context, logger *slog.Logger, path string) ([]byte, error) {
-		// check that the path exists
-		logger.Info("scanning for vulnerabilities", "path", path)
-		info, err := os.Stat(path)
+	return func(ctx context.Context, logger *slog.Logger, target Target) (*Report, error) {
+		args, err := getArgs(logger, target)
 		if err != nil {
-			return nil, fmt.Errorf("invalid scan path '%s': %w", path, err)
+			return nil, failure.New(failure.KindConfig, err)
 		}
-		if !info.IsDir() {
-			return nil, fmt.Errorf("path '%s' is not a directory: %w", path, err)
+		c := scan.Command(ctx, args...)
+		if env := target.environ(); len(env) > 0 {
+			c.Env = append(os.Environ(), env...)
 		}
-		c := scan.Command(ctx, "-C", path, "-format", "json", "./...")
-		stdout := &bytes.Buffer{}
-		c.Stdout = stdout
+		pr, pw := io.Pipe()
+		c.Stdout = pw
 		c.Stderr = stderr
+		var progress []string
+		type result struct {
+			report *Report
+			err    error
+		}
+		results := make(chan result, 1)
+		go func() {
+			report, err := parseReport(pr, func(p *Progress) {
+				logger.Debug("govulncheck progress", "message", p.Message)
+				progress = append(progress, p.Message)
+			})
+			// keep reading the output after a decoding error, so that govulncheck is never blocked
+			_, _ = io.Copy(io.Discard, pr)
+			results <- result{report: report, err: err}
+		}()
 		if err := c.Start(); err != nil {
+			pw.Close()
+			<-results
 			return nil, fmt.Errorf("failed to start golang/govulncheck: %w", err)
 		}
-		if err := c.Wait(); err != nil {
-			fmt.Fprintf(stderr, "%s", stdout.String())
-			return nil, fmt.Errorf("failed while running golang/govulncheck: %w", err)
+		err = c.Wait()
+		pw.Close()
+		res := <-results
+		if err != nil {
+			for _, p := range progress {
+				fmt.Fprintln(stderr, p)
+			}
+			return nil, failure.New(classify(ctx, err), fmt.Errorf("failed while running golang/govulncheck: %w", err))
 		}
-		if logger.Enabled(ctx, slog.LevelDebug) {
-			logger.Debug("govulncheck output", "output", stdout.String())
+		if res.err != nil {
+			return nil, fmt.Errorf("failed to parse: %w", res.err)
 		}
-		return stdout.Bytes(), nil
+		return res.report, nil
+	}
+}
+
+// joinDiagnostics returns the diagnostics as a single string
+func joinDiagnostics(diagnostics []Diagnostic) string {
+	msgs := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		msgs = append(msgs, d.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// logConfig logs the configuration of the scan which produced a report
+func logConfig(logger *slog.Logger, config *Config) {
+	attrs := []any{
+		"scanner", config.ScannerName + "@" + config.ScannerVersion,
+		"go_version", config.GoVersion,
+		"db", config.DB,
+		"scan_level", config.ScanLevel,
+	}
+	if config.DBLastModified != nil {
+		attrs = append(attrs, "db_last_modified", config.DBLastModified.UTC().Format(time.RFC3339))
+	}
+	logger.Info("govulncheck report", attrs...)
+}
+
+// classify returns the kind of failure of an error returned by the govulncheck command,
+// based on the messages of the errors in golang.org/x/vuln
+func classify(ctx context.Context, err error) failure.Kind {
+	msg := err.Error()
+	switch {
+	case ctx.Err() != nil:
+		return failure.KindTimeout
+	case strings.Contains(msg, "fetching vulnerabilities"), strings.Contains(msg, "creating client"):
+		return failure.KindDB
+	case strings.Contains(msg, "loading packages"), strings.Contains(msg, "Loading packages failed"),
+		strings.Contains(msg, "no go.mod file"), strings.Contains(msg, "unrecognized binary format"):
+		return failure.KindBuild
+	default:
+		return failure.KindUnknown
+	}
+}
+
+// getArgs returns the arguments of the govulncheck command for the given target
+func getArgs(logger *slog.Logger, target Target) ([]string, error) {
+	args := []string{"-format", "json"}
+	if target.ScanLevel != "" {
+		args = append(args, "-scan", target.ScanLevel)
+	}
+	if target.DB != "" {
+		args = append(args, "-db", target.DB)
+	}
+	if target.Test {
+		args = append(args, "-test")
+	}
+	if target.Context.Tags != "" {
+		args = append(args, "-tags", target.Context.Tags)
+	}
+	if target.Binary != "" {
+		// check that the binary exists
+		logger.Info("scanning binary for vulnerabilities", "path", target.BinaryName())
+		binary := target.BinaryPath()
+		info, err := os.Stat(binary)
+		if err != nil {
+			return nil, fmt.Errorf("invalid binary path '%s': %w", binary, err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("binary path '%s' is a directory", binary)
+		}
+		return append(args, "-mode", ModeBinary, binary), nil
+	}
+	// check that the path exists
+	logger.Info("scanning for vulnerabilities", "path", target.Dir)
+	info, err := os.Stat(target.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan path '%s': %w", target.Dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path '%s' is not a directory: %w", target.Dir, err)
+	}
+	args = append(args, "-C", target.Dir)
+	if len(target.Packages) == 0 {
+		return append(args, "./..."), nil
 	}
+	return append(args, target.Packages...), nil
 }
@@ -1,14 +1,22 @@
 package govulncheck_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/failure"
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/govulncheck"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,15 +26,15 @@ func TestScan(t *testing.T) {
 
 	t.Run("no vuln found", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
 			return nil, nil
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -36,15 +44,15 @@ func TestScan(t *testing.T) {
 
 	t.Run("2 vulns found", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
-			return os.ReadFile("../testdata/valid_report.json")
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -52,10 +60,109 @@ func TestScan(t *testing.T) {
 		assert.Empty(t, outdatedVulns)
 	})
 
+	t.Run("2 vulns found with severities", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{
+			Path: "./...",
+			SeverityData: severity.Data{
+				"CVE-2025-22871": {Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", Score: 7.5},
+				"GO-2025-3547":   {Score: 8.2},
+			},
+			MinSeverity: 8,
+		}
+
+		// when
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, vulns, 2)
+		// sorted by decreasing score
+		assert.Equal(t, "GO-2025-3547", vulns[0].ID)
+		assert.Empty(t, vulns[0].Informational)
+		assert.Equal(t, "GO-2025-3563", vulns[1].ID)
+		assert.Equal(t, "the severity is below the minimum severity (8.0)", vulns[1].Informational)
+		assert.Empty(t, outdatedVulns)
+	})
+
+	t.Run("vulns reachable from test code only", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			report, err := readReport("../testdata/valid_report.json")
+			if err != nil {
+				return nil, err
+			}
+			if !target.Test {
+				// GO-2025-3547 is not called from the production code
+				delete(report.Finding, "GO-2025-3547")
+				return report, nil
+			}
+			// the shortest call stacks of both vulnerable symbols start in test files
+			for _, findings := range report.Finding {
+				for _, f := range findings {
+					entry := &f.Trace[len(f.Trace)-1]
+					entry.Position.Filename = strings.TrimSuffix(entry.Position.Filename, ".go") + "_test.go"
+				}
+			}
+			return report, nil
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{Path: "./...", IncludeTests: true}
+
+		// when
+		vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, vulns, 2)
+		for _, v := range vulns {
+			if v.ID == "GO-2025-3547" {
+				assert.True(t, v.TestOnly, v.ID)
+			} else {
+				// also called from the production code
+				assert.False(t, v.TestOnly, v.ID)
+			}
+		}
+	})
+
+	t.Run("2 vulns found and 1 in the baseline", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// the baseline is the result of a previous scan
+		baseline, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: "./..."}, config)
+		require.NoError(t, err)
+		require.Len(t, baseline, 2)
+		opts := govulncheck.Options{
+			Path:     "./...",
+			Baseline: baseline[1:],
+		}
+
+		// when
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, vulns, 2)
+		assert.Empty(t, vulns[0].Informational)
+		assert.Equal(t, "the vulnerability was already found in the baseline", vulns[1].Informational)
+		assert.Equal(t, baseline[1].Fingerprints, vulns[1].Fingerprints)
+		assert.Empty(t, outdatedVulns)
+	})
+
 	t.Run("2 vulns found and 1 ignored", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
-			return os.ReadFile("../testdata/valid_report.json")
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{
@@ -66,10 +173,10 @@ func TestScan(t *testing.T) {
 				},
 			},
 		}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -79,8 +186,8 @@ func TestScan(t *testing.T) {
 
 	t.Run("2 vulns found and 1 ignored and 1 expired", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
-			return os.ReadFile("../testdata/valid_report.json")
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{
@@ -95,10 +202,10 @@ func TestScan(t *testing.T) {
 				},
 			},
 		}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -108,8 +215,8 @@ func TestScan(t *testing.T) {
 
 	t.Run("2 vulns found and 2 ignored", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
-			return os.ReadFile("../testdata/valid_report.json")
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{
@@ -124,10 +231,10 @@ func TestScan(t *testing.T) {
 				},
 			},
 		}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -135,10 +242,55 @@ func TestScan(t *testing.T) {
 		assert.Empty(t, outdatedVulns)
 	})
 
+	t.Run("ignored vuln which is not called anymore is outdated", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			report, err := readReport("../testdata/valid_report.json")
+			if err != nil {
+				return nil, err
+			}
+			// the vulnerable package of GO-2025-3547 is still imported, but its vulnerable symbols are not called anymore
+			for _, f := range report.Finding["GO-2025-3547"] {
+				f.Trace = f.Trace[:1]
+				f.Trace[0].Function = ""
+			}
+			return report, nil
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{
+			IgnoredVulnerabilities: []*configuration.Vulnerability{
+				{
+					ID:           "GO-2025-3547",
+					SilenceUntil: time.Now().Add(24 * time.Hour),
+				},
+			},
+		}
+
+		t.Run("unreachable vulns are informational", func(t *testing.T) {
+			// when
+			vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: "./..."}, config)
+			// then
+			require.NoError(t, err)
+			require.Len(t, vulns, 1)
+			assert.Equal(t, "GO-2025-3563", vulns[0].ID)
+			require.Len(t, outdatedVulns, 1)
+			assert.Equal(t, "GO-2025-3547", outdatedVulns[0].ID)
+		})
+
+		t.Run("unreachable vulns fail the scan", func(t *testing.T) {
+			// when
+			vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: "./...", FailUnreachable: true}, config)
+			// then
+			require.NoError(t, err)
+			require.Len(t, vulns, 1)
+			assert.Empty(t, outdatedVulns)
+		})
+	})
+
 	t.Run("2 vulns found and 2 ignored and 1 outdated", func(t *testing.T) {
 		// given
-		scan := func(ctx context.Context, logger *slog.Logger, _ string) ([]byte, error) {
-			return os.ReadFile("../testdata/valid_report.json")
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return readReport("../testdata/valid_report.json")
 		}
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 		config := configuration.Configuration{
@@ -157,10 +309,10 @@ func TestScan(t *testing.T) {
 				},
 			},
 		}
-		path := "./..."
+		opts := govulncheck.Options{Path: "./..."}
 
 		// when
-		vulns, outdatedVulns, err := govulncheck.Scan(context.Background(), logger, scan, path, config)
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
 
 		// then
 		require.NoError(t, err)
@@ -169,4 +321,413 @@ func TestScan(t *testing.T) {
 		assert.Equal(t, "GO-0000-0000", outdatedVulns[0].ID)
 	})
 
+	t.Run("2 vulns found in 2 workspace modules", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common")
+		var scanned []string
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			scanned = append(scanned, target.Module)
+			if target.Module == "example.com/common" {
+				// no vulnerability in this module
+				return nil, nil
+			}
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path}, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/operator", "example.com/common"}, scanned)
+		require.Len(t, vulns, 2)
+		assert.Equal(t, []string{"example.com/operator"}, vulns[0].Modules)
+		assert.Equal(t, []string{"operator/main.go:46:2\n", "operator/pkg/cri/containers.go:39:52\n"}, vulns[0].Traces)
+		assert.Equal(t, []string{"example.com/operator"}, vulns[1].Modules)
+		assert.Empty(t, outdatedVulns)
+	})
+
+	t.Run("environment of the workspace modules", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common")
+		env := []string{"GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1"}
+		var scanned [][]string
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			scanned = append(scanned, target.Env)
+			return nil, nil
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		_, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path, Env: env}, config)
+		// then
+		require.NoError(t, err)
+		gowork := "GOWORK=" + filepath.Join(path, "go.work")
+		assert.Equal(t, [][]string{
+			{gowork, "GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1"},
+			{gowork, "GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1"},
+		}, scanned)
+	})
+
+	t.Run("workspace modules scanned in parallel", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common", "api")
+		var running, maxRunning atomic.Int32
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			if target.Module == "example.com/operator" {
+				// wait a bit longer, so that this target completes last
+				time.Sleep(50 * time.Millisecond)
+			}
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path, Parallelism: 2}, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), maxRunning.Load())
+		require.Len(t, vulns, 2)
+		// modules and traces are merged in the order of the workspace modules
+		assert.Equal(t, []string{"example.com/operator", "example.com/common", "example.com/api"}, vulns[0].Modules)
+		assert.Equal(t, []string{
+			"operator/main.go:46:2\n", "operator/pkg/cri/containers.go:39:52\n",
+			"common/main.go:46:2\n", "common/pkg/cri/containers.go:39:52\n",
+			"api/main.go:46:2\n", "api/pkg/cri/containers.go:39:52\n",
+		}, vulns[0].Traces)
+	})
+
+	t.Run("scan error in one of the workspace modules", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common")
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			if target.Module == "example.com/common" {
+				return nil, fmt.Errorf("mock error")
+			}
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		_, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path, Parallelism: 2}, config)
+		// then
+		require.EqualError(t, err, "mock error")
+	})
+
+	t.Run("partial report when the scan times out", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common")
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			if target.Module == "example.com/common" {
+				// never completes before the timeout
+				<-ctx.Done()
+				return nil, failure.New(failure.KindTimeout, ctx.Err())
+			}
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{
+			IgnoredVulnerabilities: []*configuration.Vulnerability{
+				{
+					ID:           "GO-0000-0000", // non-existing vulnerability
+					SilenceUntil: time.Now().Add(24 * time.Hour),
+				},
+			},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		// when
+		vulns, outdatedVulns, _, err := govulncheck.Scan(ctx, logger, scan, govulncheck.Options{Path: path, Parallelism: 2}, config)
+		// then
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, failure.KindTimeout, failure.KindOf(err))
+		require.Len(t, vulns, 2)
+		assert.Equal(t, []string{"example.com/operator"}, vulns[0].Modules)
+		// outdated vulnerabilities are not reported, since the report is partial
+		assert.Empty(t, outdatedVulns)
+	})
+
+	t.Run("all packages excluded", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(path, "go.mod"), []byte("module example.com/operator\n\ngo 1.26.0\n"), 0o600))
+		require.NoError(t, os.Mkdir(filepath.Join(path, "hack"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "hack", "tools.go"), []byte("package hack\n"), 0o600))
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			require.Fail(t, "no package to scan")
+			return nil, nil
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		vulns, _, excluded, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path, Exclude: []string{"hack"}}, config)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, vulns)
+		assert.Equal(t, []string{"example.com/operator/hack"}, excluded)
+	})
+
+	t.Run("vulns found in vendored dependencies", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(path, "go.mod"), []byte("module example.com/operator\n\ngo 1.26.0\n"), 0o600))
+		require.NoError(t, os.Mkdir(filepath.Join(path, "vendor"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "vendor", "modules.txt"), []byte("# k8s.io/kubernetes v1.30.10\n## explicit; go 1.22.0\nk8s.io/kubernetes/pkg/features\n"), 0o600))
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			assert.Equal(t, filepath.Join(path, "vendor"), target.VendorDir)
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		// when
+		vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{Path: path}, config)
+		// then
+		require.NoError(t, err)
+		require.Len(t, vulns, 2)
+		assert.Equal(t, "k8s.io/kubernetes@v1.30.10", vulns[0].Vendored)
+		assert.Empty(t, vulns[1].Vendored) // stdlib
+	})
+
+	t.Run("vulns found in a report of a previous scan", func(t *testing.T) {
+		// given
+		path := newWorkspace(t, "operator", "common") // ignored, since the report covers the whole workspace
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{
+			IgnoredVulnerabilities: []*configuration.Vulnerability{
+				{
+					ID:           "GO-2025-3563",
+					SilenceUntil: time.Now().Add(24 * time.Hour),
+				},
+			},
+		}
+		opts := govulncheck.Options{Path: path, FromReport: true}
+
+		t.Run("from file", func(t *testing.T) {
+			// given
+			scan := govulncheck.ReportScan("../testdata/valid_report.json", nil)
+			// when
+			vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+			// then
+			require.NoError(t, err)
+			require.Len(t, vulns, 1)
+			assert.Equal(t, "GO-2025-3547", vulns[0].ID)
+			assert.Empty(t, vulns[0].Modules)
+			assert.Equal(t, []string{"main.go:46:2\n", "pkg/cri/containers.go:39:52\n"}, vulns[0].Traces)
+			assert.Empty(t, outdatedVulns)
+		})
+
+		t.Run("from stdin", func(t *testing.T) {
+			// given
+			report, err := os.ReadFile("../testdata/valid_report.json")
+			require.NoError(t, err)
+			scan := govulncheck.ReportScan("-", bytes.NewReader(report))
+			// when
+			vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+			// then
+			require.NoError(t, err)
+			require.Len(t, vulns, 1)
+			assert.Equal(t, "GO-2025-3547", vulns[0].ID)
+		})
+
+		t.Run("missing file", func(t *testing.T) {
+			// given
+			scan := govulncheck.ReportScan("../testdata/missing.json", nil)
+			// when
+			_, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+			// then
+			require.ErrorContains(t, err, "failed to read report")
+			assert.Equal(t, failure.KindConfig, failure.KindOf(err))
+		})
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return govulncheck.ParseReport(strings.NewReader(`{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[]}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12","package":"net/http/internal","function":"Read"}]}}`))
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+
+		t.Run("invalid findings are skipped", func(t *testing.T) {
+			// when
+			vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{FromReport: true}, config)
+			// then
+			require.NoError(t, err)
+			require.Len(t, vulns, 1)
+			assert.Equal(t, "GO-2025-3563", vulns[0].ID)
+			assert.Equal(t, "https://pkg.go.dev/vuln/GO-2025-3563", vulns[0].MoreInfo)
+			assert.Equal(t, []string{"net/http/internal.Read\n"}, vulns[0].Traces)
+		})
+
+		t.Run("strict report", func(t *testing.T) {
+			// when
+			_, _, _, err := govulncheck.Scan(context.Background(), logger, scan, govulncheck.Options{FromReport: true, StrictReport: true}, config)
+			// then
+			require.EqualError(t, err, "invalid govulncheck report: GO-2025-3563: finding without trace; GO-2025-3563: no OSV entry for the finding")
+		})
+	})
+
+	t.Run("2 vulns found in binaries", func(t *testing.T) {
+		// given
+		var scanned []string
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			scanned = append(scanned, target.Binary)
+			return govulncheck.ParseReport(strings.NewReader(`{"osv":{"id":"GO-2025-3563","summary":"Request smuggling"}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12","package":"net/http/internal","function":"Read"}]}}`))
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{
+			Mode:     govulncheck.ModeBinary,
+			Binaries: []string{"bin/manager", "bin/webhook"},
+		}
+		// when
+		vulns, outdatedVulns, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bin/manager", "bin/webhook"}, scanned)
+		require.Len(t, vulns, 1)
+		assert.Equal(t, "GO-2025-3563", vulns[0].ID)
+		assert.Equal(t, []string{"bin/manager", "bin/webhook"}, vulns[0].Binaries)
+		assert.Equal(t, []string{"bin/manager: net/http/internal.Read\n", "bin/webhook: net/http/internal.Read\n"}, vulns[0].Traces)
+		assert.Empty(t, outdatedVulns)
+	})
+
+	t.Run("vuln found in the binaries of an image archive", func(t *testing.T) {
+		// given
+		archive := newImageArchive(t, "/usr/local/bin/manager", "/usr/local/bin/webhook")
+		var scanned []string
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			require.FileExists(t, target.BinaryPath())
+			scanned = append(scanned, target.BinaryName())
+			return govulncheck.ParseReport(strings.NewReader(`{"osv":{"id":"GO-2025-3563","summary":"Request smuggling"}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12","package":"net/http/internal","function":"Read"}]}}`))
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{
+			Mode:          govulncheck.ModeBinary,
+			ImageArchives: []string{archive},
+		}
+		// when
+		vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+		// then
+		require.NoError(t, err)
+		expected := []string{archive + ":/usr/local/bin/manager", archive + ":/usr/local/bin/webhook"}
+		assert.Equal(t, expected, scanned)
+		require.Len(t, vulns, 1)
+		assert.Equal(t, expected, vulns[0].Binaries)
+	})
+
+	t.Run("vulns found in some build contexts", func(t *testing.T) {
+		// given
+		var scanned []string
+		scan := func(ctx context.Context, logger *slog.Logger, target govulncheck.Target) (*govulncheck.Report, error) {
+			scanned = append(scanned, target.Context.String())
+			if target.Context.GOARCH == "amd64" && target.Context.Tags == "" {
+				// no vulnerability in this context
+				return nil, nil
+			}
+			return readReport("../testdata/valid_report.json")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{
+			Path:      "./...",
+			Platforms: []string{"linux/amd64", "linux/arm64"},
+			Tags:      []string{"", "e2e"},
+		}
+		// when
+		vulns, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"linux/amd64", "linux/amd64,tags=e2e", "linux/arm64", "linux/arm64,tags=e2e"}, scanned)
+		require.Len(t, vulns, 2)
+		assert.Equal(t, []string{"linux/amd64,tags=e2e", "linux/arm64", "linux/arm64,tags=e2e"}, vulns[0].Contexts)
+		assert.Equal(t, []string{"linux/amd64,tags=e2e", "linux/arm64", "linux/arm64,tags=e2e"}, vulns[1].Contexts)
+	})
+
+	t.Run("invalid platform", func(t *testing.T) {
+		// given
+		scan := func(ctx context.Context, logger *slog.Logger, _ govulncheck.Target) (*govulncheck.Report, error) {
+			return nil, nil
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		config := configuration.Configuration{}
+		opts := govulncheck.Options{
+			Path:      "./...",
+			Platforms: []string{"linux"},
+		}
+		// when
+		_, _, _, err := govulncheck.Scan(context.Background(), logger, scan, opts, config)
+		// then
+		require.EqualError(t, err, "invalid platform: 'linux' (must be '<os>/<arch>' or '<os>/<arch>/<variant>')")
+		assert.Equal(t, failure.KindConfig, failure.KindOf(err))
+	})
+}
+
+// readReport parses the govulncheck JSON report in the given file
+func readReport(path string) (*govulncheck.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return govulncheck.ParseReport(f)
+}
+
+// newImageArchive creates a `docker save` archive with a single layer containing a copy of the test binary
+// (which is a Go binary) at each given path
+func newImageArchive(t *testing.T, binaries ...string) string {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	layer := &bytes.Buffer{}
+	lw := tar.NewWriter(layer)
+	for _, b := range binaries {
+		require.NoError(t, lw.WriteHeader(&tar.Header{Name: b, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := lw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, lw.Close())
+	path := filepath.Join(t.TempDir(), "operator.tar")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for name, data := range map[string][]byte{
+		"manifest.json": []byte(`[{"Layers":["layer.tar"]}]`),
+		"layer.tar":     layer.Bytes(),
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return path
+}
+
+// newWorkspace creates a Go workspace with a module in a subdirectory for each given name
+func newWorkspace(t *testing.T, modules ...string) string {
+	path := t.TempDir()
+	gowork := "go 1.26.0\n\nuse (\n"
+	for _, m := range modules {
+		gowork += "\t./" + m + "\n"
+		require.NoError(t, os.Mkdir(filepath.Join(path, m), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(path, m, "go.mod"), []byte("module example.com/"+m+"\n"), 0o600))
+	}
+	gowork += ")\n"
+	require.NoError(t, os.WriteFile(filepath.Join(path, "go.work"), []byte(gowork), 0o600))
+	return path
 }
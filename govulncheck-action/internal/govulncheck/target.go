@@ -0,0 +1,267 @@
+package govulncheck
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/image"
+	"golang.org/x/mod/modfile"
+)
+
+// Target is a single unit of work for the underlying govulncheck command
+type Target struct {
+	// Dir is the directory in which govulncheck is run
+	Dir string
+	// ModuleDir is the directory of the module, relative to the scanned path
+	// (empty when the scanned path is not a Go workspace)
+	ModuleDir string
+	// Module is the path of the module declared in its `go.mod` file
+	// (empty when the scanned path is not a Go workspace)
+	Module string
+	// Binary is the path to the binary to scan in `binary` mode
+	// (or its path in the image when the binary is extracted from an image archive)
+	Binary string
+	// Image is the path to the image archive from which the binary is extracted (empty for a standalone binary)
+	Image string
+	// ScanLevel is the level of the analysis (`module`, `package` or `symbol`, or empty for the default level)
+	ScanLevel string
+	// DB is the URL of the vulnerability database (or empty for the default database)
+	DB string
+	// Test is true if the test files are analyzed
+	Test bool
+	// Packages are the patterns (or import paths) of the packages to scan in `source` mode (`./...` if empty)
+	Packages []string
+	// Context is the build context in which the source code is analyzed (or empty for the default context)
+	Context BuildContext
+	// VendorDir is the `vendor` directory of the module (or workspace) when the dependencies are vendored,
+	// in which case the packages are loaded in `-mod=vendor` mode
+	VendorDir string
+	// Env contains the additional environment variables to set when running govulncheck
+	Env []string
+}
+
+// BinaryPath returns the path to the binary file to scan
+// (in the directory in which the image was extracted when the binary is extracted from an image archive)
+func (t Target) BinaryPath() string {
+	if t.Image != "" {
+		return filepath.Join(t.Dir, filepath.FromSlash(t.Binary))
+	}
+	return t.Binary
+}
+
+// BinaryName returns the name of the binary in the results, eg: `bin/manager` or `manager.tar:/usr/local/bin/manager`
+func (t Target) BinaryName() string {
+	if t.Image != "" {
+		return t.Image + ":" + t.Binary
+	}
+	return t.Binary
+}
+
+// environ returns the additional environment variables to set when running the Go tools for the target
+func (t Target) environ() []string {
+	env := slices.Clone(t.Env)
+	if t.Context.GOOS != "" {
+		env = append(env, "GOOS="+t.Context.GOOS, "GOARCH="+t.Context.GOARCH)
+	}
+	if t.Context.Variant != "" {
+		name, value, _ := variantEnv(t.Context.GOARCH, t.Context.Variant)
+		env = append(env, name+"="+value)
+	}
+	if t.VendorDir != "" {
+		// the `-mod` flag takes precedence over the one that may already be set in the environment
+		env = append(env, "GOFLAGS="+strings.TrimSpace(os.Getenv("GOFLAGS")+" -mod=vendor"))
+	}
+	return env
+}
+
+// getTargets returns the targets to scan.
+// When evaluating the report of a previous scan, there is a single target.
+// In `binary` mode, there is one target per binary.
+// Otherwise, if the path contains a `go.work` file, then there is one target per module listed in the `use` directives,
+// otherwise, the path itself is the single target.
+func getTargets(opts Options) ([]Target, error) {
+	if opts.FromReport {
+		// the report of a previous scan, which is not attributed to any module or binary
+		return []Target{{Dir: opts.Path}}, nil
+	}
+	if opts.Mode == ModeBinary {
+		if len(opts.Binaries) == 0 && len(opts.ImageArchives) == 0 {
+			return nil, fmt.Errorf("no binary to scan in '%s' mode", ModeBinary)
+		}
+		targets := make([]Target, 0, len(opts.Binaries))
+		for _, b := range opts.Binaries {
+			targets = append(targets, Target{Binary: b})
+		}
+		return targets, nil
+	}
+	path := opts.Path
+	goworkPath := filepath.Join(path, "go.work")
+	contents, err := os.ReadFile(goworkPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Target{{Dir: path}}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read `go.work` file: %w", err)
+	}
+	gowork, err := modfile.ParseWork(goworkPath, contents, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse `go.work` file: %w", err)
+	}
+	if len(gowork.Use) == 0 {
+		return nil, fmt.Errorf("no module listed in '%s'", goworkPath)
+	}
+	// make sure that the `go.work` file is used even if the module is not in a subdirectory of the workspace
+	absGoworkPath, err := filepath.Abs(goworkPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of '%s': %w", goworkPath, err)
+	}
+	targets := make([]Target, 0, len(gowork.Use))
+	for _, use := range gowork.Use {
+		dir := filepath.Join(path, use.Path)
+		gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read `go.mod` file of workspace module '%s': %w", use.Path, err)
+		}
+		targets = append(targets, Target{
+			Dir:       dir,
+			ModuleDir: filepath.ToSlash(filepath.Clean(use.Path)),
+			Module:    modfile.ModulePath(gomod),
+			Env:       []string{"GOWORK=" + absGoworkPath},
+		})
+	}
+	return targets, nil
+}
+
+// getImageTargets extracts the Go binaries of the given image archives into subdirectories of the given directory,
+// and returns a target for each of them
+func getImageTargets(logger *slog.Logger, archives []string, dir string) ([]Target, error) {
+	var targets []Target
+	for i, archive := range archives {
+		rootfs, binaries, err := image.Extract(archive, filepath.Join(dir, strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+		if len(binaries) == 0 {
+			logger.Warn("no Go binary found in image archive", "archive", archive)
+			continue
+		}
+		logger.Info("Go binaries found in image archive", "archive", archive, "binaries", binaries)
+		for _, b := range binaries {
+			targets = append(targets, Target{
+				Dir:    rootfs,
+				Binary: b,
+				Image:  archive,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// BuildContext is a combination of target platform and build tags in which the source code is analyzed
+type BuildContext struct {
+	GOOS   string
+	GOARCH string
+	// Variant is the variant of the architecture (eg: `v7` for `arm`), mapped to the `GOARM`, `GOARM64` or `GOAMD64` variable
+	Variant string
+	// Tags is a comma-separated list of build tags
+	Tags string
+}
+
+// String returns the representation of the build context, eg: `linux/arm64`, `linux/arm/v7` or `linux/arm64,tags=e2e`
+// (empty for the default build context)
+func (c BuildContext) String() string {
+	var s string
+	if c.GOOS != "" {
+		s = c.GOOS + "/" + c.GOARCH
+	}
+	if c.Variant != "" {
+		s += "/" + c.Variant
+	}
+	if c.Tags != "" {
+		if s != "" {
+			s += ","
+		}
+		s += "tags=" + c.Tags
+	}
+	return s
+}
+
+// variantEnv returns the name and value of the environment variable that selects the given variant of the architecture,
+// using the variants of the OCI image platforms (eg: `v7` for `arm`, `v8` for `arm64` or `v3` for `amd64`)
+func variantEnv(goarch, variant string) (string, string, error) {
+	switch goarch {
+	case "arm":
+		if v := strings.TrimPrefix(variant, "v"); slices.Contains([]string{"5", "6", "7"}, v) {
+			return "GOARM", v, nil
+		}
+	case "arm64":
+		if v := strings.TrimPrefix(variant, "v"); v != variant && v != "" {
+			if !strings.Contains(v, ".") {
+				// `v8` is `v8.0` for the Go toolchain
+				variant += ".0"
+			}
+			return "GOARM64", variant, nil
+		}
+	case "amd64":
+		if slices.Contains([]string{"v1", "v2", "v3", "v4"}, variant) {
+			return "GOAMD64", variant, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported variant '%s' for the '%s' architecture", variant, goarch)
+}
+
+// getBuildContexts returns the build contexts for all the combinations of the given platforms (in the `<os>/<arch>`
+// or `<os>/<arch>/<variant>` format) and build tags (each item being a comma-separated list of tags)
+func getBuildContexts(platforms, tags []string) ([]BuildContext, error) {
+	if len(platforms) == 0 {
+		// default platform
+		platforms = []string{""}
+	}
+	if len(tags) == 0 {
+		// no build tags
+		tags = []string{""}
+	}
+	contexts := make([]BuildContext, 0, len(platforms)*len(tags))
+	for _, p := range platforms {
+		var goos, goarch, variant string
+		if p != "" {
+			parts := strings.Split(p, "/")
+			if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+				return nil, fmt.Errorf("invalid platform: '%s' (must be '<os>/<arch>' or '<os>/<arch>/<variant>')", p)
+			}
+			goos, goarch = parts[0], parts[1]
+			if len(parts) == 3 {
+				variant = parts[2]
+				if _, _, err := variantEnv(goarch, variant); err != nil {
+					return nil, fmt.Errorf("invalid platform: '%s': %w", p, err)
+				}
+			}
+		}
+		for _, t := range tags {
+			contexts = append(contexts, BuildContext{
+				GOOS:    goos,
+				GOARCH:  goarch,
+				Variant: variant,
+				Tags:    t,
+			})
+		}
+	}
+	return contexts, nil
+}
+
+// withBuildContexts returns a copy of each target for each build context
+func withBuildContexts(targets []Target, contexts []BuildContext) []Target {
+	result := make([]Target, 0, len(targets)*len(contexts))
+	for _, t := range targets {
+		for _, c := range contexts {
+			t.Context = c
+			result = append(result, t)
+		}
+	}
+	return result
+}
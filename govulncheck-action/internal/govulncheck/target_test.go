@@ -0,0 +1,223 @@
+package govulncheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTargets(t *testing.T) {
+
+	t.Run("no go.work file", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		// when
+		targets, err := getTargets(Options{Path: path})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Target{{Dir: path}}, targets)
+	})
+
+	t.Run("go.work file with modules", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "go.work"), "go 1.26.0\n\nuse (\n\t./host-operator\n\t./toolchain-common\n)\n")
+		writeFile(t, filepath.Join(path, "host-operator", "go.mod"), "module github.com/codeready-toolchain/host-operator\n\ngo 1.26.0\n")
+		writeFile(t, filepath.Join(path, "toolchain-common", "go.mod"), "module github.com/codeready-toolchain/toolchain-common\n\ngo 1.26.0\n")
+		// when
+		targets, err := getTargets(Options{Path: path})
+		// then
+		require.NoError(t, err)
+		gowork, err := filepath.Abs(filepath.Join(path, "go.work"))
+		require.NoError(t, err)
+		assert.Equal(t, []Target{
+			{
+				Dir:       filepath.Join(path, "host-operator"),
+				ModuleDir: "host-operator",
+				Module:    "github.com/codeready-toolchain/host-operator",
+				Env:       []string{"GOWORK=" + gowork},
+			},
+			{
+				Dir:       filepath.Join(path, "toolchain-common"),
+				ModuleDir: "toolchain-common",
+				Module:    "github.com/codeready-toolchain/toolchain-common",
+				Env:       []string{"GOWORK=" + gowork},
+			},
+		}, targets)
+	})
+
+	t.Run("binaries", func(t *testing.T) {
+		// when
+		targets, err := getTargets(Options{Path: ".", Mode: ModeBinary, Binaries: []string{"bin/manager", "bin/webhook"}})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Target{{Binary: "bin/manager"}, {Binary: "bin/webhook"}}, targets)
+	})
+
+	t.Run("no binary", func(t *testing.T) {
+		// when
+		_, err := getTargets(Options{Path: ".", Mode: ModeBinary})
+		// then
+		require.EqualError(t, err, "no binary to scan in 'binary' mode")
+	})
+
+	t.Run("go.work file without modules", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "go.work"), "go 1.26.0\n")
+		// when
+		_, err := getTargets(Options{Path: path})
+		// then
+		require.ErrorContains(t, err, "no module listed in")
+	})
+
+	t.Run("missing go.mod file in workspace module", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "go.work"), "go 1.26.0\n\nuse ./host-operator\n")
+		// when
+		_, err := getTargets(Options{Path: path})
+		// then
+		require.ErrorContains(t, err, "failed to read `go.mod` file of workspace module './host-operator'")
+	})
+
+	t.Run("invalid go.work file", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "go.work"), "invalid\n")
+		// when
+		_, err := getTargets(Options{Path: path})
+		// then
+		require.ErrorContains(t, err, "failed to parse `go.work` file")
+	})
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+}
+
+func TestGetBuildContexts(t *testing.T) {
+
+	t.Run("default build context", func(t *testing.T) {
+		// when
+		contexts, err := getBuildContexts(nil, nil)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []BuildContext{{}}, contexts)
+		assert.Empty(t, contexts[0].String())
+	})
+
+	t.Run("platforms and tags", func(t *testing.T) {
+		// when
+		contexts, err := getBuildContexts([]string{"linux/amd64", "linux/arm64"}, []string{"", "e2e,integration"})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []BuildContext{
+			{GOOS: "linux", GOARCH: "amd64"},
+			{GOOS: "linux", GOARCH: "amd64", Tags: "e2e,integration"},
+			{GOOS: "linux", GOARCH: "arm64"},
+			{GOOS: "linux", GOARCH: "arm64", Tags: "e2e,integration"},
+		}, contexts)
+		assert.Equal(t, "linux/amd64", contexts[0].String())
+		assert.Equal(t, "linux/amd64,tags=e2e,integration", contexts[1].String())
+	})
+
+	t.Run("tags only", func(t *testing.T) {
+		// when
+		contexts, err := getBuildContexts(nil, []string{"e2e"})
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []BuildContext{{Tags: "e2e"}}, contexts)
+		assert.Equal(t, "tags=e2e", contexts[0].String())
+	})
+
+	t.Run("platforms with variants", func(t *testing.T) {
+		// when
+		contexts, err := getBuildContexts([]string{"linux/arm/v7", "linux/arm64/v8", "linux/amd64/v3"}, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []BuildContext{
+			{GOOS: "linux", GOARCH: "arm", Variant: "v7"},
+			{GOOS: "linux", GOARCH: "arm64", Variant: "v8"},
+			{GOOS: "linux", GOARCH: "amd64", Variant: "v3"},
+		}, contexts)
+		assert.Equal(t, "linux/arm/v7", contexts[0].String())
+	})
+
+	for _, p := range []string{"linux", "linux/", "/amd64", "linux/arm/", "linux/arm/v7/extra"} {
+		t.Run("invalid platform "+p, func(t *testing.T) {
+			// when
+			_, err := getBuildContexts([]string{p}, nil)
+			// then
+			require.EqualError(t, err, "invalid platform: '"+p+"' (must be '<os>/<arch>' or '<os>/<arch>/<variant>')")
+		})
+	}
+
+	for _, p := range []string{"linux/arm/v8", "linux/amd64/v5", "linux/386/sse2"} {
+		t.Run("unsupported variant "+p, func(t *testing.T) {
+			// when
+			_, err := getBuildContexts([]string{p}, nil)
+			// then
+			require.ErrorContains(t, err, "invalid platform: '"+p+"': unsupported variant")
+		})
+	}
+}
+
+func TestWithBuildContexts(t *testing.T) {
+	// given
+	targets := []Target{{Dir: "operator"}, {Dir: "common"}}
+	contexts := []BuildContext{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "linux", GOARCH: "arm64"}}
+	// when
+	result := withBuildContexts(targets, contexts)
+	// then
+	assert.Equal(t, []Target{
+		{Dir: "operator", Context: contexts[0]},
+		{Dir: "operator", Context: contexts[1]},
+		{Dir: "common", Context: contexts[0]},
+		{Dir: "common", Context: contexts[1]},
+	}, result)
+}
+
+func TestEnviron(t *testing.T) {
+	// given
+	t.Setenv("GOFLAGS", "-trimpath")
+	target := Target{
+		Dir:       "operator",
+		Context:   BuildContext{GOOS: "linux", GOARCH: "arm64"},
+		VendorDir: "operator/vendor",
+		Env:       []string{"GOWORK=/work/go.work", "GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1"},
+	}
+	// when
+	env := target.environ()
+	// then
+	assert.Equal(t, []string{
+		"GOWORK=/work/go.work", "GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1",
+		"GOOS=linux", "GOARCH=arm64",
+		"GOFLAGS=-trimpath -mod=vendor",
+	}, env)
+	assert.Equal(t, []string{"GOWORK=/work/go.work", "GOTOOLCHAIN=go1.26.1", "GOVERSION=go1.26.1"}, target.Env) // unchanged
+
+	t.Run("variants", func(t *testing.T) {
+		testCases := map[string]string{
+			"arm/v7":   "GOARM=7",
+			"arm64/v8": "GOARM64=v8.0",
+			"amd64/v3": "GOAMD64=v3",
+		}
+		for platform, expected := range testCases {
+			t.Run(platform, func(t *testing.T) {
+				// given
+				goarch, variant, _ := strings.Cut(platform, "/")
+				target := Target{Context: BuildContext{GOOS: "linux", GOARCH: goarch, Variant: variant}}
+				// when
+				env := target.environ()
+				// then
+				assert.Equal(t, []string{"GOOS=linux", "GOARCH=" + goarch, expected}, env)
+			})
+		}
+	})
+}
@@ -1,10 +1,44 @@
 package govulncheck
 
+import (
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+)
+
+// Message is a message of the govulncheck JSON output, which contains a single non-nil field
+// see https://pkg.go.dev/golang.org/x/vuln/internal/govulncheck
+type Message struct {
+	Config   *Config   `json:"config,omitempty"`
+	Progress *Progress `json:"progress,omitempty"`
+	OSV      *OSV      `json:"osv,omitempty"`
+	Finding  *Finding  `json:"finding,omitempty"`
+}
+
+// Config is the configuration of the scan, sent first in the output
+type Config struct {
+	ProtocolVersion string     `json:"protocol_version,omitempty"`
+	ScannerName     string     `json:"scanner_name,omitempty"`
+	ScannerVersion  string     `json:"scanner_version,omitempty"`
+	DB              string     `json:"db,omitempty"`
+	DBLastModified  *time.Time `json:"db_last_modified,omitempty"`
+	GoVersion       string     `json:"go_version,omitempty"`
+	ScanLevel       string     `json:"scan_level,omitempty"`
+	ScanMode        string     `json:"scan_mode,omitempty"`
+}
+
+// Progress is a message about the progress of the scan
+type Progress struct {
+	Message string `json:"message"`
+}
+
 type Trace struct {
-	Module   string   `json:"module"`
-	Version  string   `json:"version"`
-	Package  string   `json:"package"`
-	Function string   `json:"function"`
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	// Receiver is the receiver type of the function, if it is a method (eg: `*remoteRuntimeService`)
+	Receiver string   `json:"receiver,omitempty"`
 	Position Position `json:"position"`
 }
 
@@ -20,16 +54,62 @@ type Finding struct {
 	Trace        []Trace `json:"trace"`
 }
 
+const (
+	// ReviewStatusReviewed is the review status of the advisories which were reviewed by the Go security team
+	ReviewStatusReviewed = "REVIEWED"
+	// ReviewStatusUnreviewed is the review status of the advisories which were automatically imported
+	// from another database (eg: GHSA) and not reviewed yet
+	ReviewStatusUnreviewed = "UNREVIEWED"
+)
+
 type DatabaseSpecific struct {
 	URL string `json:"url"`
+	// ReviewStatus is `REVIEWED` or `UNREVIEWED`
+	ReviewStatus string `json:"review_status,omitempty"`
 }
+
+// OSV is an entry of the vulnerability database
+// see https://ossf.github.io/osv-schema/
 type OSV struct {
 	ID               string           `json:"id"`
+	Modified         *time.Time       `json:"modified,omitempty"`
+	Published        *time.Time       `json:"published,omitempty"`
+	Aliases          []string         `json:"aliases,omitempty"`
 	Summary          string           `json:"summary"`
+	Affected         []OSVAffected    `json:"affected,omitempty"`
 	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
 }
 
+// OSVAffected is a module affected by an OSV entry, with the ranges of affected versions
+type OSVAffected struct {
+	Module OSVModule `json:"package"`
+	Ranges []Range   `json:"ranges,omitempty"`
+}
+
+// OSVModule is the module of an affected entry (`stdlib` for the standard library)
+type OSVModule struct {
+	Path      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// Range is a range of affected versions, described by the versions in which the vulnerability was introduced and fixed
+type Range struct {
+	Type   string       `json:"type"`
+	Events []RangeEvent `json:"events"`
+}
+
+// RangeEvent is an event of a range of affected versions, with either an introduced or a fixed version
+// (without the `v` prefix, `0` meaning all the versions)
+type RangeEvent struct {
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+}
+
 type Report struct {
+	// Config is the configuration of the scan (nil if the report has no `config` message)
+	Config *Config `json:"config,omitempty"`
+	// Progress contains the progress messages, in the order in which they were sent
+	Progress []*Progress `json:"progress,omitempty"`
 	// 1* findings per vuln
 	Finding map[string][]*Finding `json:"finding,omitempty"`
 	// 1 OSV per vuln
@@ -37,10 +117,78 @@ type Report struct {
 }
 
 type Vulnerability struct {
-	ID       string
-	Summary  string
-	MoreInfo string
-	FoundIn  string
-	FixedIn  string
-	Traces   []string
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	MoreInfo string `json:"more_info"`
+	// Module is the path of the module of the vulnerable package (`stdlib` for the standard library)
+	Module  string `json:"module"`
+	FoundIn string `json:"-"`
+	FixedIn string `json:"-"`
+	// Affected contains all the versions of the modules affected by the vulnerability, with their fixed versions
+	Affected []Affected `json:"affected,omitempty"`
+	// Aliases are the other IDs of the vulnerability (eg: `CVE-2025-22871`)
+	Aliases []string `json:"aliases,omitempty"`
+	// Published is the time when the advisory was published (zero if unknown)
+	Published time.Time `json:"published,omitzero"`
+	// Modified is the time when the advisory was last modified (zero if unknown)
+	Modified time.Time `json:"modified,omitzero"`
+	// ReviewStatus is the review status of the advisory (`REVIEWED` or `UNREVIEWED`, empty if unknown)
+	ReviewStatus string `json:"review_status,omitempty"`
+	// Ranges contains the ranges of affected versions of each module of the advisory
+	// (eg: `stdlib: < 1.23.8`)
+	Ranges []string `json:"ranges,omitempty"`
+	// Severity is the CVSS severity of the vulnerability, from the severity data (nil if unknown)
+	Severity *severity.Severity `json:"severity,omitempty"`
+	Traces   []string           `json:"-"`
+	// CallStacks contains the full call stack of each trace (in the same order as the traces)
+	CallStacks []CallStack `json:"call_stacks,omitempty"`
+	// Fingerprints contains the fingerprints of the findings of the vulnerability (sorted and without duplicates),
+	// which are stable across runs (eg: `5d3c3f5c1a2b9e07`)
+	Fingerprints []string `json:"fingerprints"`
+	// Level is the most precise level at which the vulnerability was found (`module`, `package` or `symbol`)
+	Level string `json:"level"`
+	// TestOnly is true if the vulnerable symbols are only called from test code (`_test.go` files or `test/e2e` packages)
+	TestOnly bool `json:"test_only,omitempty"`
+	// Informational is the reason why the vulnerability is reported without failing the scan
+	// (empty if the vulnerability fails the scan)
+	Informational string `json:"informational,omitempty"`
+	// Modules contains the workspace modules in which the vulnerability was found
+	Modules []string `json:"modules,omitempty"`
+	// Binaries contains the binaries in which the vulnerability was found
+	Binaries []string `json:"binaries,omitempty"`
+	// Contexts contains the build contexts in which the vulnerability was found
+	Contexts []string `json:"contexts,omitempty"`
+	// Vendored is the version of the module in the `vendor/modules.txt` file
+	// (empty if the dependencies are not vendored)
+	Vendored string `json:"vendored,omitempty"`
+}
+
+// CallStack is a chain of calls from an entry point to a vulnerable symbol
+type CallStack struct {
+	// Binary is the binary in which the call stack was found (empty in source mode)
+	Binary string `json:"binary,omitempty"`
+	// Frames are the frames of the call stack, from the entry point to the vulnerable symbol
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a function in a call stack
+type Frame struct {
+	// Module is the path of the module of the function
+	Module string `json:"module"`
+	// Function is the qualified name of the function, including its receiver
+	// (eg: `k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ListContainers`)
+	Function string `json:"function"`
+	// Position is the `<file>:<line>:<column>` location of the call to the next frame,
+	// or of the vulnerable symbol in the last frame (empty if unknown, eg: in binary mode)
+	Position string `json:"position,omitempty"`
+}
+
+// Affected is a version of a module affected by a vulnerability
+type Affected struct {
+	// Module is the path of the module (`stdlib` for the standard library)
+	Module string `json:"module"`
+	// Version is the version of the module which is used (eg: `v0.33.0`, or `v1.22.12` for the standard library)
+	Version string `json:"version"`
+	// FixedVersion is the version of the module in which the vulnerability is fixed (empty if there is no fix)
+	FixedVersion string `json:"fixed_version,omitempty"`
 }
@@ -0,0 +1,94 @@
+package govulncheck
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Upgrade is the upgrade of a module which fixes its vulnerabilities
+type Upgrade struct {
+	// Module is the path of the module (`stdlib` for the standard library)
+	Module string
+	// Version is the version which fixes all the fixable vulnerabilities of the module,
+	// i.e., the maximum of their fixed versions (empty if none of them can be fixed)
+	Version string
+	// Fixed contains the IDs of the vulnerabilities fixed by the upgrade
+	Fixed []string
+	// Unfixed contains the IDs of the vulnerabilities of the module which have no fix
+	Unfixed []string
+}
+
+// GetUpgrades groups the given vulnerabilities by affected module, and returns the upgrade of each module (sorted by module).
+// A vulnerability which has no fix in one of the affected versions of a module is not fixed by the upgrade of this module.
+func GetUpgrades(vulns []*Vulnerability) []*Upgrade {
+	upgrades := make(map[string]*Upgrade)
+	for _, v := range vulns {
+		for _, a := range v.Affected {
+			u, found := upgrades[a.Module]
+			if !found {
+				u = &Upgrade{Module: a.Module}
+				upgrades[a.Module] = u
+			}
+			if a.FixedVersion == "" {
+				u.Unfixed = appendUnique(u.Unfixed, v.ID)
+				continue
+			}
+			u.Fixed = appendUnique(u.Fixed, v.ID)
+			if u.Version == "" || semver.Compare(a.FixedVersion, u.Version) > 0 {
+				u.Version = a.FixedVersion
+			}
+		}
+	}
+	result := make([]*Upgrade, 0, len(upgrades))
+	for _, u := range upgrades {
+		u.Fixed = slices.DeleteFunc(u.Fixed, func(id string) bool {
+			return slices.Contains(u.Unfixed, id)
+		})
+		if len(u.Fixed) == 0 {
+			u.Version = ""
+		}
+		sort.Strings(u.Fixed)
+		sort.Strings(u.Unfixed)
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Module < result[j].Module
+	})
+	return result
+}
+
+// PrintUpgrades prints the recommended upgrades
+// example:
+//
+//	Recommended upgrades:
+//	  upgrade golang.org/x/net to v0.38.0 fixes 3 vulnerabilities: GO-2025-3503, GO-2025-3595, GO-2025-3770
+//	  upgrade stdlib to go1.23.8 fixes 1 vulnerability: GO-2025-3563
+//	  no fix available in k8s.io/kubernetes for 1 vulnerability: GO-2025-3547
+func PrintUpgrades(stdout io.Writer, upgrades []*Upgrade) {
+	if len(upgrades) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout, "Recommended upgrades:")
+	for _, u := range upgrades {
+		if len(u.Fixed) > 0 {
+			fmt.Fprintf(stdout, "  upgrade %s to %s fixes %s: %s\n", u.Module, displayVersion(u.Module, u.Version), countVulns(len(u.Fixed)), strings.Join(u.Fixed, ", "))
+		}
+		if len(u.Unfixed) > 0 {
+			fmt.Fprintf(stdout, "  no fix available in %s for %s: %s\n", u.Module, countVulns(len(u.Unfixed)), strings.Join(u.Unfixed, ", "))
+		}
+	}
+	fmt.Fprintln(stdout, "")
+}
+
+// countVulns returns the number of vulnerabilities, with the singular or plural noun
+func countVulns(count int) string {
+	if count == 1 {
+		return "1 vulnerability"
+	}
+	return fmt.Sprintf("%d vulnerabilities", count)
+}
@@ -0,0 +1,94 @@
+package govulncheck
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUpgrades(t *testing.T) {
+	// given
+	vulns := []*Vulnerability{
+		{ID: "GO-2025-3595", Affected: []Affected{{Module: "golang.org/x/net", Version: "v0.35.0", FixedVersion: "v0.38.0"}}},
+		{ID: "GO-2025-3503", Affected: []Affected{
+			{Module: "golang.org/x/net", Version: "v0.33.0", FixedVersion: "v0.36.0"},
+			{Module: "golang.org/x/net", Version: "v0.35.0", FixedVersion: "v0.36.0"},
+		}},
+		{ID: "GO-2025-3563", Affected: []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}}},
+		{ID: "GO-2025-3770", Affected: []Affected{{Module: "golang.org/x/net", Version: "v0.35.0", FixedVersion: "v0.38.1"}}},
+		{ID: "GO-2025-3547", Affected: []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}}},
+		{ID: "GO-2025-3373", Affected: []Affected{{Module: "stdlib", Version: "v1.22.10", FixedVersion: "v1.22.11"}}},
+		// a vulnerability in several modules
+		{ID: "GO-2024-2687", Affected: []Affected{
+			{Module: "golang.org/x/net", Version: "v0.35.0", FixedVersion: "v0.23.0"},
+			{Module: "k8s.io/apimachinery", Version: "v0.30.0"},
+		}},
+	}
+
+	// when
+	upgrades := GetUpgrades(vulns)
+
+	// then
+	assert.Equal(t, []*Upgrade{
+		{
+			Module:  "golang.org/x/net",
+			Version: "v0.38.1",
+			Fixed:   []string{"GO-2024-2687", "GO-2025-3503", "GO-2025-3595", "GO-2025-3770"},
+		},
+		{
+			Module:  "k8s.io/apimachinery",
+			Unfixed: []string{"GO-2024-2687"},
+		},
+		{
+			Module:  "k8s.io/kubernetes",
+			Unfixed: []string{"GO-2025-3547"},
+		},
+		{
+			Module:  "stdlib",
+			Version: "v1.23.8",
+			Fixed:   []string{"GO-2025-3373", "GO-2025-3563"},
+		},
+	}, upgrades)
+
+	t.Run("print upgrades", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintUpgrades(&buf, upgrades)
+		// then
+		assert.Equal(t, "Recommended upgrades:\n"+
+			"  upgrade golang.org/x/net to v0.38.1 fixes 4 vulnerabilities: GO-2024-2687, GO-2025-3503, GO-2025-3595, GO-2025-3770\n"+
+			"  no fix available in k8s.io/apimachinery for 1 vulnerability: GO-2024-2687\n"+
+			"  no fix available in k8s.io/kubernetes for 1 vulnerability: GO-2025-3547\n"+
+			"  upgrade stdlib to go1.23.8 fixes 2 vulnerabilities: GO-2025-3373, GO-2025-3563\n"+
+			"\n", buf.String())
+	})
+
+	t.Run("vulnerability not fixed in all the affected versions", func(t *testing.T) {
+		// when
+		upgrades := GetUpgrades([]*Vulnerability{
+			{ID: "GO-2025-0001", Affected: []Affected{
+				{Module: "example.com/lib", Version: "v1.0.0", FixedVersion: "v1.0.1"},
+				{Module: "example.com/lib", Version: "v2.0.0"},
+			}},
+		})
+		// then
+		assert.Equal(t, []*Upgrade{
+			{
+				Module:  "example.com/lib",
+				Fixed:   []string{},
+				Unfixed: []string{"GO-2025-0001"},
+			},
+		}, upgrades)
+	})
+
+	t.Run("no upgrade", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintUpgrades(&buf, GetUpgrades(nil))
+		// then
+		assert.Empty(t, buf.String())
+	})
+}
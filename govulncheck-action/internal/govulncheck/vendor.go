@@ -0,0 +1,69 @@
+package govulncheck
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VendorDir returns the path to the `vendor` directory of the module (or workspace) in the given path,
+// or an empty string if the dependencies are not vendored (ie: there is no `vendor/modules.txt` file)
+func VendorDir(path string) (string, error) {
+	dir := filepath.Join(path, "vendor")
+	if _, err := os.Stat(filepath.Join(dir, "modules.txt")); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to check vendor directory: %w", err)
+	}
+	return dir, nil
+}
+
+// readVendoredModules returns the modules listed in the `modules.txt` file of the given vendor directory,
+// along with their vendored version (eg: `golang.org/x/net@v0.33.0`, or `golang.org/x/net@v0.33.0 => example.com/net@v0.34.0`
+// when the module is replaced)
+func readVendoredModules(vendorDir string) (map[string]string, error) {
+	contents, err := os.ReadFile(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendored modules: %w", err)
+	}
+	modules := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(contents))
+	for s.Scan() {
+		// module lines are `# <path> <version>`, optionally followed by `=> <path> [<version>]` for replaced modules,
+		// while `## ...` lines are annotations and other lines are the vendored packages
+		line, found := strings.CutPrefix(s.Text(), "# ")
+		if !found {
+			continue
+		}
+		module, replacement, _ := strings.Cut(line, "=>")
+		fields := strings.Fields(module)
+		if len(fields) == 0 {
+			continue
+		}
+		vendored := strings.Join(fields, "@")
+		if r := strings.Fields(replacement); len(r) > 0 {
+			vendored += " => " + strings.Join(r, "@")
+		}
+		if _, found := modules[fields[0]]; !found {
+			// the replacements which apply to all versions are listed again at the end of the file, without the vendored version
+			modules[fields[0]] = vendored
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vendored modules: %w", err)
+	}
+	return modules, nil
+}
+
+// attributeVendoredModules records the vendored version of the modules in which the vulnerabilities were found
+func attributeVendoredModules(vulns []*Vulnerability, modules map[string]string) {
+	for _, v := range vulns {
+		if vendored, found := modules[v.Module]; found {
+			v.Vendored = vendored
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package govulncheck
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const modulesTxt = `# github.com/go-logr/logr v1.4.2
+## explicit; go 1.18
+github.com/go-logr/logr
+# golang.org/x/net v0.33.0 => github.com/example/net v0.34.0
+## explicit; go 1.18
+golang.org/x/net/http2
+# k8s.io/api v0.31.4 => ../api
+## explicit; go 1.22.0
+k8s.io/api/core/v1
+# golang.org/x/net => github.com/example/net v0.34.0
+`
+
+func TestVendorDir(t *testing.T) {
+
+	t.Run("vendored dependencies", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "vendor", "modules.txt"), modulesTxt)
+		// when
+		dir, err := VendorDir(path)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(path, "vendor"), dir)
+	})
+
+	t.Run("vendor directory without modules.txt file", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		writeFile(t, filepath.Join(path, "vendor", "README.md"), "not vendored dependencies")
+		// when
+		dir, err := VendorDir(path)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, dir)
+	})
+}
+
+func TestReadVendoredModules(t *testing.T) {
+	// given
+	path := t.TempDir()
+	writeFile(t, filepath.Join(path, "vendor", "modules.txt"), modulesTxt)
+	// when
+	modules, err := readVendoredModules(filepath.Join(path, "vendor"))
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"github.com/go-logr/logr": "github.com/go-logr/logr@v1.4.2",
+		"golang.org/x/net":        "golang.org/x/net@v0.33.0 => github.com/example/net@v0.34.0",
+		"k8s.io/api":              "k8s.io/api@v0.31.4 => ../api",
+	}, modules)
+}
+
+func TestAttributeVendoredModules(t *testing.T) {
+	// given
+	vulns := []*Vulnerability{
+		{ID: "GO-2025-0001", Module: "golang.org/x/net"},
+		{ID: "GO-2025-0002", Module: "stdlib"},
+	}
+	// when
+	attributeVendoredModules(vulns, map[string]string{
+		"golang.org/x/net": "golang.org/x/net@v0.33.0",
+	})
+	// then
+	assert.Equal(t, "golang.org/x/net@v0.33.0", vulns[0].Vendored)
+	assert.Empty(t, vulns[1].Vendored)
+
+	t.Run("print vendored version", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintVulnerabilities(&buf, vulns, TracesCompact)
+		// then
+		assert.Contains(t, buf.String(), "  Vendored: golang.org/x/net@v0.33.0\n")
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("Vendored:")))
+	})
+}
+
+func TestEnvironWithVendorDir(t *testing.T) {
+	// given
+	t.Setenv("GOFLAGS", "-mod=mod -trimpath")
+	target := Target{Dir: "operator", VendorDir: "operator/vendor"}
+	// when
+	env := target.environ()
+	// then
+	assert.Equal(t, []string{"GOFLAGS=-mod=mod -trimpath -mod=vendor"}, env)
+}
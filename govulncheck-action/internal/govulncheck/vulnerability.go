@@ -1,32 +1,155 @@
 package govulncheck
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"log/slog"
+	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+	"golang.org/x/mod/semver"
 )
 
 // getTracesInfo gets all the locations where the vulnerability is presented
 // <file>:<line>:<column>
 // example: pkg/configuration/config.go:95:26
+// or the vulnerable symbol when there is no position (eg: in binary mode)
+// <package>.<function>
+// example: net/http/internal.(*chunkedReader).Read
 func getTracesInfo(findings []*Finding) []string {
 	traceInfo := make([]string, 0)
 	for _, f := range findings {
 		// the location of the file is presented on the last item of the trace
 		trace := f.Trace[len(f.Trace)-1]
 		info := fmt.Sprintf("%s:%s:%s\n", trace.Position.Filename, strconv.Itoa(trace.Position.Line), strconv.Itoa(trace.Position.Column))
+		if trace.Position.Filename == "" {
+			info = getFunction(trace) + "\n"
+		}
 
 		traceInfo = append(traceInfo, info)
 	}
 	return traceInfo
 }
 
+// getCallStacks gets the full call stack of each finding, from the entry point (the last item of the trace)
+// to the vulnerable symbol (the first item of the trace)
+func getCallStacks(findings []*Finding) []CallStack {
+	stacks := make([]CallStack, 0, len(findings))
+	for _, f := range findings {
+		frames := make([]Frame, 0, len(f.Trace))
+		for i := len(f.Trace) - 1; i >= 0; i-- {
+			frame := Frame{
+				Module:   f.Trace[i].Module,
+				Function: getFunction(f.Trace[i]),
+			}
+			if p := f.Trace[i].Position; p.Filename != "" {
+				frame.Position = fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+			}
+			frames = append(frames, frame)
+		}
+		stacks = append(stacks, CallStack{Frames: frames})
+	}
+	return stacks
+}
+
+// getFunction returns the qualified name of the function of the trace item, including its receiver
+// example: k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ListContainers
+func getFunction(trace Trace) string {
+	switch {
+	case trace.Receiver == "":
+		return fmt.Sprintf("%s.%s", trace.Package, trace.Function)
+	case strings.HasPrefix(trace.Receiver, "*"):
+		return fmt.Sprintf("%s.(%s).%s", trace.Package, trace.Receiver, trace.Function)
+	default:
+		return fmt.Sprintf("%s.%s.%s", trace.Package, trace.Receiver, trace.Function)
+	}
+}
+
+// getAffected gets all the module versions of the findings, with their fixed versions
+// (the affected module is presented in the first item of the trace)
+func getAffected(findings []*Finding) []Affected {
+	affected := make([]Affected, 0, len(findings))
+	for _, f := range findings {
+		affected = appendUnique(affected, Affected{
+			Module:       f.Trace[0].Module,
+			Version:      f.Trace[0].Version,
+			FixedVersion: f.FixedVersion,
+		})
+	}
+	sortAffected(affected)
+	return affected
+}
+
+// sortAffected sorts the affected versions by module, then by version
+func sortAffected(affected []Affected) {
+	slices.SortFunc(affected, func(a, b Affected) int {
+		return cmp.Or(
+			strings.Compare(a.Module, b.Module),
+			semver.Compare(a.Version, b.Version),
+			semver.Compare(a.FixedVersion, b.FixedVersion),
+		)
+	})
+}
+
+// String returns the module version with its fixed version
+// example: golang.org/x/net@v0.33.0 (fixed in v0.38.0)
+func (a Affected) String() string {
+	if a.FixedVersion == "" {
+		return fmt.Sprintf("%s@%s (no fix available)", a.Module, displayVersion(a.Module, a.Version))
+	}
+	return fmt.Sprintf("%s@%s (fixed in %s)", a.Module, displayVersion(a.Module, a.Version), displayVersion(a.Module, a.FixedVersion))
+}
+
+// getRanges gets the ranges of affected versions of each module of the advisory
+// example: stdlib: < 1.22.12; >= 1.23.0-0, < 1.23.8
+func getRanges(affected []OSVAffected) []string {
+	var ranges []string
+	for _, a := range affected {
+		var intervals []string
+		for _, r := range a.Ranges {
+			introduced := ""
+			for _, e := range r.Events {
+				switch {
+				case e.Introduced != "":
+					introduced = e.Introduced
+				case e.Fixed != "" && (introduced == "" || introduced == "0"):
+					intervals = append(intervals, "< "+e.Fixed)
+					introduced = ""
+				case e.Fixed != "":
+					intervals = append(intervals, fmt.Sprintf(">= %s, < %s", introduced, e.Fixed))
+					introduced = ""
+				}
+			}
+			switch {
+			case introduced == "0":
+				intervals = append(intervals, "all versions")
+			case introduced != "":
+				intervals = append(intervals, ">= "+introduced)
+			}
+		}
+		if len(intervals) > 0 {
+			ranges = append(ranges, fmt.Sprintf("%s: %s", a.Module.Path, strings.Join(intervals, "; ")))
+		}
+	}
+	return ranges
+}
+
+// displayVersion returns the version as it is displayed: Go versions (eg: `go1.22.12`) for the standard library,
+// and module versions (eg: `v0.33.0`) for the other modules
+func displayVersion(module, version string) string {
+	if isStdLib(module) {
+		return "go" + strings.TrimPrefix(version, "v")
+	}
+	return version
+}
+
 func isStdLib(module string) bool {
 	return module == "stdlib"
 }
@@ -44,34 +167,379 @@ func getVersion(isStdLib bool, msg, pkg, version string) string {
 	return fmt.Sprintf("%s: %s@%s", msg, pkg, version)
 }
 
-func getVulnerabilities(rawReport []byte) ([]*Vulnerability, error) {
-	var vulns []*Vulnerability
+// getMostPreciseFindings returns the most precise level of the given findings, along with the findings at this level
+func getMostPreciseFindings(findings []*Finding) (string, []*Finding) {
+	level := ScanLevelModule
+	for _, f := range findings {
+		if l := getLevel(f.Trace); isLevelBelow(level, l) {
+			level = l
+		}
+	}
+	preciseFindings := make([]*Finding, 0, len(findings))
+	for _, f := range findings {
+		if getLevel(f.Trace) == level {
+			preciseFindings = append(preciseFindings, f)
+		}
+	}
+	return level, preciseFindings
+}
+
+// sortFindings sorts the findings by the position and function of their entry point (the last item of the trace),
+// then by vulnerable module version and symbol (the first item of the trace)
+func sortFindings(findings []*Finding) {
+	slices.SortStableFunc(findings, func(a, b *Finding) int {
+		entryA, entryB := a.Trace[len(a.Trace)-1], b.Trace[len(b.Trace)-1]
+		return cmp.Or(
+			strings.Compare(entryA.Position.Filename, entryB.Position.Filename),
+			cmp.Compare(entryA.Position.Line, entryB.Position.Line),
+			cmp.Compare(entryA.Position.Column, entryB.Position.Column),
+			strings.Compare(getFunction(entryA), getFunction(entryB)),
+			strings.Compare(a.Trace[0].Module, b.Trace[0].Module),
+			semver.Compare(a.Trace[0].Version, b.Trace[0].Version),
+			strings.Compare(getFunction(a.Trace[0]), getFunction(b.Trace[0])),
+		)
+	})
+}
 
-	report, err := parseReport(rawReport)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse: %w", err)
+// isTestOnly returns true if all the findings are reachable from test code only,
+// i.e., the entry point of the trace (the last item) is in a `_test.go` file or in a `test/e2e` package
+func isTestOnly(findings []*Finding) bool {
+	for _, f := range findings {
+		entry := f.Trace[len(f.Trace)-1]
+		if !strings.HasSuffix(entry.Position.Filename, "_test.go") && !strings.Contains("/"+entry.Package+"/", "/test/e2e/") {
+			return false
+		}
+	}
+	return len(findings) > 0
+}
+
+// getVulnerabilities returns the vulnerabilities of the findings in the report (none if the report is nil)
+func getVulnerabilities(report *Report) []*Vulnerability {
+	var vulns []*Vulnerability
+	if report == nil {
+		return vulns
 	}
 
 	for id := range report.Finding {
-		isStandard := isStdLib(report.Finding[id][0].Trace[0].Module)
+		// only keep the findings at the most precise level
+		level, findings := getMostPreciseFindings(report.Finding[id])
+		// the order of the findings in the output of govulncheck is not deterministic
+		sortFindings(findings)
+		isStandard := isStdLib(findings[0].Trace[0].Module)
 		// the target package is presented in the first item of the trace
-		pkg := report.Finding[id][0].Trace[0].Package
-
-		vulns = append(vulns, &Vulnerability{
-			ID:       id,
-			Summary:  report.OSV[id].Summary,
-			MoreInfo: report.OSV[id].DatabaseSpecific.URL,
-			FoundIn:  getVersion(isStandard, "Found in", pkg, report.Finding[id][0].Trace[0].Version),
-			FixedIn:  getVersion(isStandard, "Fixed in", pkg, report.Finding[id][0].FixedVersion),
-			Traces:   getTracesInfo(report.Finding[id]),
-		})
+		// (or only the module, when the vulnerable package is not imported)
+		pkg := findings[0].Trace[0].Package
+		if pkg == "" {
+			pkg = findings[0].Trace[0].Module
+		}
+		// the traces are only available when the vulnerable symbols are called
+		traces := []string{}
+		var callStacks []CallStack
+		testOnly := false
+		if level == ScanLevelSymbol {
+			traces = getTracesInfo(findings)
+			callStacks = getCallStacks(findings)
+			testOnly = isTestOnly(findings)
+		}
+
+		osv := report.OSV[id]
+		vuln := &Vulnerability{
+			ID:           id,
+			Summary:      osv.Summary,
+			MoreInfo:     osv.DatabaseSpecific.URL,
+			Module:       findings[0].Trace[0].Module,
+			FoundIn:      getVersion(isStandard, "Found in", pkg, findings[0].Trace[0].Version),
+			FixedIn:      getVersion(isStandard, "Fixed in", pkg, findings[0].FixedVersion),
+			Affected:     getAffected(report.Finding[id]),
+			Aliases:      osv.Aliases,
+			ReviewStatus: osv.DatabaseSpecific.ReviewStatus,
+			Ranges:       getRanges(osv.Affected),
+			Traces:       traces,
+			CallStacks:   callStacks,
+			Level:        level,
+			TestOnly:     testOnly,
+		}
+		if osv.Published != nil {
+			vuln.Published = *osv.Published
+		}
+		if osv.Modified != nil {
+			vuln.Modified = *osv.Modified
+		}
+		vulns = append(vulns, vuln)
 	}
 
 	sort.Slice(vulns, func(i, j int) bool {
 		return vulns[i].ID < vulns[j].ID
 	})
 
-	return vulns, nil
+	return vulns
+}
+
+// attributeVulnerabilities records the workspace module or the binary, and the build context in which the vulnerabilities were found
+// and makes their traces relative to the scanned path
+func attributeVulnerabilities(target Target, vulns []*Vulnerability) {
+	if c := target.Context.String(); c != "" {
+		for _, v := range vulns {
+			v.Contexts = []string{c}
+		}
+	}
+	switch {
+	case target.Binary != "":
+		for _, v := range vulns {
+			v.Binaries = []string{target.BinaryName()}
+			for i, trace := range v.Traces {
+				v.Traces[i] = fmt.Sprintf("%s: %s", target.BinaryName(), trace)
+			}
+			for i := range v.CallStacks {
+				v.CallStacks[i].Binary = target.BinaryName()
+			}
+		}
+	case target.Module != "":
+		for _, v := range vulns {
+			v.Modules = []string{target.Module}
+			for i, trace := range v.Traces {
+				v.Traces[i] = path.Join(target.ModuleDir, trace)
+			}
+			for _, stack := range v.CallStacks {
+				for i, frame := range stack.Frames {
+					// only the positions in the workspace module are relative to its directory
+					if frame.Module == target.Module && frame.Position != "" {
+						stack.Frames[i].Position = path.Join(target.ModuleDir, frame.Position)
+					}
+				}
+			}
+		}
+	}
+}
+
+// mergeVulnerabilities merges the vulnerabilities found in multiple targets,
+// so that each vulnerability is reported once with all its traces and modules
+func mergeVulnerabilities(results ...[]*Vulnerability) []*Vulnerability {
+	merged := make(map[string]*Vulnerability)
+	var vulns []*Vulnerability
+	for _, result := range results {
+		for _, v := range result {
+			existing, found := merged[v.ID]
+			if !found {
+				merged[v.ID] = v
+				vulns = append(vulns, v)
+				continue
+			}
+			switch {
+			case isLevelBelow(existing.Level, v.Level):
+				// only keep the vulnerability found at the most precise level,
+				// but all the affected versions are kept
+				affected := existing.Affected
+				*existing = *v
+				existing.Affected = appendUnique(existing.Affected, affected...)
+				sortAffected(existing.Affected)
+			case isLevelBelow(v.Level, existing.Level):
+				existing.Affected = appendUnique(existing.Affected, v.Affected...)
+				sortAffected(existing.Affected)
+			case existing.Level == v.Level:
+				existing.Traces = append(existing.Traces, v.Traces...)
+				existing.CallStacks = append(existing.CallStacks, v.CallStacks...)
+				existing.Fingerprints = appendUnique(existing.Fingerprints, v.Fingerprints...)
+				slices.Sort(existing.Fingerprints)
+				existing.Modules = appendUnique(existing.Modules, v.Modules...)
+				existing.Binaries = appendUnique(existing.Binaries, v.Binaries...)
+				existing.Contexts = appendUnique(existing.Contexts, v.Contexts...)
+				existing.TestOnly = existing.TestOnly && v.TestOnly
+				existing.Vendored = cmp.Or(existing.Vendored, v.Vendored)
+				existing.Affected = appendUnique(existing.Affected, v.Affected...)
+				sortAffected(existing.Affected)
+			}
+		}
+	}
+	sort.Slice(vulns, func(i, j int) bool {
+		return vulns[i].ID < vulns[j].ID
+	})
+	return vulns
+}
+
+// appendUnique appends the values which are not already in the slice
+func appendUnique[T comparable](slice []T, values ...T) []T {
+	for _, v := range values {
+		if !slices.Contains(slice, v) {
+			slice = append(slice, v)
+		}
+	}
+	return slice
+}
+
+// classifyUnreachableVulns marks the vulnerabilities which were found at a less precise level than the scan level
+// as informational, unless they must fail the scan
+func classifyUnreachableVulns(vulns []*Vulnerability, scanLevel string, failUnreachable bool) {
+	if failUnreachable {
+		return
+	}
+	for _, v := range vulns {
+		if !isLevelBelow(v.Level, scanLevel) {
+			continue
+		}
+		switch v.Level {
+		case ScanLevelPackage:
+			v.Informational = "the vulnerable package is imported but the vulnerable symbols are not called"
+		default:
+			v.Informational = "the vulnerable module is required but the vulnerable packages are not imported"
+		}
+	}
+}
+
+// attributeTestOnly marks the vulnerabilities found when analyzing the test files as reachable from test code only
+// if their vulnerable symbols are not called when analyzing the code without the test files
+// (or only from the `test/e2e` packages)
+func attributeTestOnly(vulns, productionVulns []*Vulnerability) {
+	production := make(map[string]*Vulnerability, len(productionVulns))
+	for _, v := range productionVulns {
+		production[v.ID] = v
+	}
+	for _, v := range vulns {
+		if v.Level != ScanLevelSymbol {
+			continue
+		}
+		p, found := production[v.ID]
+		v.TestOnly = !found || p.Level != ScanLevelSymbol || p.TestOnly
+	}
+}
+
+// classifyTestOnlyVulns marks the vulnerabilities which are reachable from test code only as informational,
+// unless they must fail the scan
+func classifyTestOnlyVulns(vulns []*Vulnerability, failTestOnly bool) {
+	if failTestOnly {
+		return
+	}
+	for _, v := range vulns {
+		if v.TestOnly && v.Informational == "" {
+			v.Informational = "the vulnerable symbols are only called from test code"
+		}
+	}
+}
+
+// classifyUnreviewedVulns marks the vulnerabilities whose advisory is unreviewed and was published
+// less than the given number of days ago as informational (none if the number of days is not positive).
+// The unreviewed advisories whose publication date is unknown still fail the scan.
+func classifyUnreviewedVulns(vulns []*Vulnerability, warnDays int) {
+	if warnDays <= 0 {
+		return
+	}
+	for _, v := range vulns {
+		if v.ReviewStatus != ReviewStatusUnreviewed || v.Published.IsZero() || v.Informational != "" {
+			continue
+		}
+		if time.Since(v.Published) < time.Duration(warnDays)*24*time.Hour {
+			v.Informational = fmt.Sprintf("the advisory is unreviewed and was published less than %d days ago", warnDays)
+		}
+	}
+}
+
+// enrichSeverity sets the severity of the vulnerabilities which are in the severity data (by ID or alias),
+// and sorts the vulnerabilities by decreasing score, the ones whose severity is unknown being last
+func enrichSeverity(vulns []*Vulnerability, data severity.Data) {
+	if data == nil {
+		return
+	}
+	for _, v := range vulns {
+		if s, found := data.Lookup(append([]string{v.ID}, v.Aliases...)...); found {
+			v.Severity = &s
+		}
+	}
+	// the vulnerabilities are already sorted by ID
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Severity == nil || vulns[j].Severity == nil {
+			return vulns[i].Severity != nil && vulns[j].Severity == nil
+		}
+		return vulns[i].Severity.Score > vulns[j].Severity.Score
+	})
+}
+
+// classifyMinSeverityVulns marks the vulnerabilities whose score is below the minimum severity as informational.
+// The vulnerabilities whose severity is unknown still fail the scan.
+func classifyMinSeverityVulns(vulns []*Vulnerability, minSeverity float64) {
+	for _, v := range vulns {
+		if v.Severity != nil && v.Severity.Score < minSeverity && v.Informational == "" {
+			v.Informational = fmt.Sprintf("the severity is below the minimum severity (%.1f)", minSeverity)
+		}
+	}
+}
+
+const (
+	// FailOnAll selects all the vulnerabilities
+	FailOnAll = "all"
+	// FailOnStdLib selects the vulnerabilities in the standard library
+	FailOnStdLib = "stdlib"
+	// FailOnThirdParty selects the vulnerabilities in the other modules
+	FailOnThirdParty = "third-party"
+	// FailOnFixable selects the vulnerabilities which are fixed in a later version of all their affected modules
+	FailOnFixable = "fixable"
+	// FailOnUnfixable selects the vulnerabilities which are not fixed in at least one of their affected modules
+	FailOnUnfixable = "unfixable"
+	// FailOnReachable selects the vulnerabilities whose vulnerable symbols are called
+	FailOnReachable = "reachable"
+)
+
+// FailOnSelectors are the selectors of the vulnerabilities which fail the scan
+var FailOnSelectors = []string{FailOnAll, FailOnStdLib, FailOnThirdParty, FailOnFixable, FailOnUnfixable, FailOnReachable}
+
+// classifyFailOnVulns marks the vulnerabilities which do not match the fail-on selectors as informational
+// (none if the selectors are empty or contain `all`)
+func classifyFailOnVulns(vulns []*Vulnerability, selectors []string) {
+	if len(selectors) == 0 || slices.Contains(selectors, FailOnAll) {
+		return
+	}
+	for _, v := range vulns {
+		if v.Informational == "" && !matchesFailOn(v, selectors) {
+			v.Informational = fmt.Sprintf("the vulnerability does not match the fail-on selectors (%s)", strings.Join(selectors, ", "))
+		}
+	}
+}
+
+// matchesFailOn returns true if the vulnerability matches the fail-on selectors:
+// the selectors of the same category (`stdlib` and `third-party`, or `fixable` and `unfixable`) are alternatives,
+// and the vulnerability must match each category of the selectors
+// (eg: `third-party,fixable` matches the vulnerabilities in the other modules than the standard library, which are fixed)
+func matchesFailOn(v *Vulnerability, selectors []string) bool {
+	stdlib, thirdParty := slices.Contains(selectors, FailOnStdLib), slices.Contains(selectors, FailOnThirdParty)
+	if (stdlib || thirdParty) && !((stdlib && isStdLib(v.Module)) || (thirdParty && !isStdLib(v.Module))) {
+		return false
+	}
+	fixable, unfixable := slices.Contains(selectors, FailOnFixable), slices.Contains(selectors, FailOnUnfixable)
+	if (fixable || unfixable) && !((fixable && isFixable(v)) || (unfixable && !isFixable(v))) {
+		return false
+	}
+	if slices.Contains(selectors, FailOnReachable) && v.Level != ScanLevelSymbol {
+		return false
+	}
+	return true
+}
+
+// isFixable returns true if the vulnerability is fixed in a later version of all its affected modules
+func isFixable(v *Vulnerability) bool {
+	return len(v.Affected) > 0 && !slices.ContainsFunc(v.Affected, func(a Affected) bool {
+		return a.FixedVersion == ""
+	})
+}
+
+// classifyBaselineVulns marks the vulnerabilities which were already found in the baseline as informational,
+// i.e., the vulnerabilities whose findings all have a fingerprint in the baseline.
+// The vulnerabilities which are new, or which have new call sites, still fail the scan.
+func classifyBaselineVulns(vulns []*Vulnerability, baseline []*Vulnerability) {
+	if baseline == nil {
+		return
+	}
+	fingerprints := make(map[string][]string, len(baseline))
+	for _, b := range baseline {
+		fingerprints[b.ID] = append(fingerprints[b.ID], b.Fingerprints...)
+	}
+	for _, v := range vulns {
+		existing, found := fingerprints[v.ID]
+		if !found || v.Informational != "" {
+			continue
+		}
+		if !slices.ContainsFunc(v.Fingerprints, func(f string) bool { return !slices.Contains(existing, f) }) {
+			v.Informational = "the vulnerability was already found in the baseline"
+		}
+	}
 }
 
 func pruneIgnoredVulns(logger *slog.Logger, detected []*Vulnerability, ignored []*configuration.Vulnerability) []*Vulnerability {
@@ -94,12 +562,15 @@ loop:
 	return vulns
 }
 
-func listOutdatedVulns(detected []*Vulnerability, ignored []*configuration.Vulnerability) []*configuration.Vulnerability {
+// listOutdatedVulns returns the ignored vulnerabilities which would not fail the scan anymore, ie: which are not detected,
+// or only at a less precise level than the scan level (eg: the vulnerable symbols are not called anymore), unless such
+// unreachable vulnerabilities fail the scan
+func listOutdatedVulns(detected []*Vulnerability, ignored []*configuration.Vulnerability, scanLevel string, failUnreachable bool) []*configuration.Vulnerability {
 	vulns := make([]*configuration.Vulnerability, 0, len(ignored))
 loop:
 	for _, i := range ignored {
 		for _, d := range detected {
-			if d.ID == i.ID {
+			if d.ID == i.ID && (failUnreachable || !isLevelBelow(d.Level, scanLevel)) {
 				continue loop
 			}
 		}
@@ -108,29 +579,125 @@ loop:
 	return vulns
 }
 
-func PrintVulnerabilities(stdout io.Writer, vulns []*Vulnerability) {
+const (
+	// TracesFull shows the full call stack of each trace, from the entry point to the vulnerable symbol
+	TracesFull = "full"
+	// TracesCompact shows the location of the entry point of each trace
+	TracesCompact = "compact"
+	// TracesNone does not show the traces
+	TracesNone = "none"
+)
+
+// PrintVulnerabilities prints the vulnerabilities, with their traces in the given format (`full`, `compact` or `none`)
+func PrintVulnerabilities(stdout io.Writer, vulns []*Vulnerability, traces string) {
 	for i, vuln := range vulns {
 		fmt.Fprintf(stdout, "Vulnerability #%d: %s\n", i+1, vuln.ID)
 		fmt.Fprintf(stdout, "  %s\n", vuln.Summary)
+		if vuln.Informational != "" {
+			fmt.Fprintf(stdout, "  Informational: %s\n", vuln.Informational)
+		} else if vuln.TestOnly {
+			fmt.Fprintln(stdout, "  Test only: the vulnerable symbols are only called from test code")
+		}
 		fmt.Fprintf(stdout, "  More info: %s\n", vuln.MoreInfo)
+		if len(vuln.Aliases) > 0 {
+			fmt.Fprintf(stdout, "  Aliases: %s\n", strings.Join(vuln.Aliases, ", "))
+		}
+		if !vuln.Published.IsZero() {
+			published := fmt.Sprintf("  Published: %s", vuln.Published.UTC().Format(time.DateOnly))
+			if !vuln.Modified.IsZero() {
+				published += fmt.Sprintf(" (modified: %s)", vuln.Modified.UTC().Format(time.DateOnly))
+			}
+			fmt.Fprintln(stdout, published)
+		}
+		if vuln.ReviewStatus != "" {
+			fmt.Fprintf(stdout, "  Review status: %s\n", vuln.ReviewStatus)
+		}
+		if vuln.Severity != nil {
+			fmt.Fprintf(stdout, "  Severity: %s\n", vuln.Severity)
+		}
+		if len(vuln.Ranges) > 0 {
+			fmt.Fprintln(stdout, "  Affected ranges:")
+			for _, r := range vuln.Ranges {
+				fmt.Fprintf(stdout, "    %s\n", r)
+			}
+		}
 		fmt.Fprintf(stdout, "  %s\n", vuln.FoundIn)
 		fmt.Fprintf(stdout, "  %s\n", vuln.FixedIn)
-		fmt.Fprintln(stdout, "  Example traces found:")
-		for idx, info := range removeDuplicates(vuln.Traces) {
-			fmt.Fprintf(stdout, "    #%d: %s", idx+1, info)
+		if len(vuln.Affected) > 1 {
+			fmt.Fprintln(stdout, "  Affected versions:")
+			for _, a := range vuln.Affected {
+				fmt.Fprintf(stdout, "    %s\n", a)
+			}
+		}
+		if vuln.Vendored != "" {
+			fmt.Fprintf(stdout, "  Vendored: %s\n", vuln.Vendored)
+		}
+		if len(vuln.Modules) > 0 {
+			fmt.Fprintf(stdout, "  Found in workspace modules: %s\n", strings.Join(vuln.Modules, ", "))
+		}
+		if len(vuln.Binaries) > 0 {
+			fmt.Fprintf(stdout, "  Found in binaries: %s\n", strings.Join(vuln.Binaries, ", "))
+		}
+		if len(vuln.Contexts) > 0 {
+			fmt.Fprintf(stdout, "  Found in build contexts: %s\n", strings.Join(vuln.Contexts, "; "))
+		}
+		if len(vuln.Fingerprints) > 0 {
+			fmt.Fprintf(stdout, "  Fingerprints: %s\n", strings.Join(vuln.Fingerprints, ", "))
+		}
+		switch {
+		case traces == TracesFull && len(vuln.CallStacks) > 0:
+			fmt.Fprintln(stdout, "  Call stacks found:")
+			for idx, stack := range removeDuplicates(formatCallStacks(vuln.CallStacks)) {
+				fmt.Fprintf(stdout, "    #%d: %s", idx+1, stack)
+			}
+		case traces != TracesNone && len(vuln.Traces) > 0:
+			fmt.Fprintln(stdout, "  Example traces found:")
+			for idx, info := range removeDuplicates(vuln.Traces) {
+				fmt.Fprintf(stdout, "    #%d: %s", idx+1, info)
+			}
 		}
 		fmt.Fprintln(stdout, "")
-		fmt.Fprintln(stdout, "if this vulnerability cannot be fixed, add the following entry in your exclusion file:")
-		fmt.Fprintf(stdout, "  # %s\n", vuln.Summary)
-		fmt.Fprintf(stdout, "  # %s\n", vuln.FoundIn)
-		fmt.Fprintf(stdout, "  # %s\n", vuln.FixedIn)
-		fmt.Fprintf(stdout, "  - id: %s\n", vuln.ID)
-		fmt.Fprintf(stdout, "    info: %s\n", vuln.MoreInfo)
-		fmt.Fprintf(stdout, "    silence-until: %s\n", time.Now().Add(30*24*time.Hour).Format("2006-01-02")) // silence for 30 days
-		fmt.Fprintln(stdout, "")
+		// the informational vulnerabilities do not fail the scan, and do not need to be ignored
+		if vuln.Informational == "" {
+			fmt.Fprintln(stdout, "if this vulnerability cannot be fixed, add the following entry in your exclusion file:")
+			fmt.Fprintf(stdout, "  # %s\n", vuln.Summary)
+			fmt.Fprintf(stdout, "  # %s\n", vuln.FoundIn)
+			fmt.Fprintf(stdout, "  # %s\n", vuln.FixedIn)
+			fmt.Fprintf(stdout, "  - id: %s\n", vuln.ID)
+			fmt.Fprintf(stdout, "    info: %s\n", vuln.MoreInfo)
+			fmt.Fprintf(stdout, "    silence-until: %s\n", time.Now().Add(30*24*time.Hour).Format("2006-01-02")) // silence for 30 days
+			fmt.Fprintln(stdout, "")
+		}
 	}
 }
 
+// formatCallStacks formats each call stack with a frame per line, from the entry point to the vulnerable symbol
+// example:
+//
+//	package/pkg/cri.GetContainersPerPID (pkg/cri/containers.go:39:52)
+//	    k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ContainerStatus (pkg/kubelet/cri/remote/remote_runtime.go:416:32)
+func formatCallStacks(stacks []CallStack) []string {
+	formatted := make([]string, 0, len(stacks))
+	for _, stack := range stacks {
+		b := &strings.Builder{}
+		if stack.Binary != "" {
+			fmt.Fprintf(b, "%s: ", stack.Binary)
+		}
+		for i, frame := range stack.Frames {
+			if i > 0 {
+				b.WriteString("        ")
+			}
+			b.WriteString(frame.Function)
+			if frame.Position != "" {
+				fmt.Fprintf(b, " (%s)", frame.Position)
+			}
+			b.WriteString("\n")
+		}
+		formatted = append(formatted, b.String())
+	}
+	return formatted
+}
+
 func PrintOutdatedVulnerabilities(stdout io.Writer, vulns []*configuration.Vulnerability) {
 	for _, vuln := range vulns {
 		fmt.Fprintf(stdout, "Vulnerability %s is outdated (must be removed from config)\n", vuln.ID)
@@ -148,3 +715,14 @@ func removeDuplicates(slice []string) []string {
 	}
 	return duplicatesRemoved
 }
+
+// FailingVulnerabilities returns the vulnerabilities which are not informational
+func FailingVulnerabilities(vulns []*Vulnerability) []*Vulnerability {
+	failing := make([]*Vulnerability, 0, len(vulns))
+	for _, v := range vulns {
+		if v.Informational == "" {
+			failing = append(failing, v)
+		}
+	}
+	return failing
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/configuration"
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,46 +17,326 @@ import (
 func TestGetVulnerabilities(t *testing.T) {
 	t.Run("get vulnerabilities", func(t *testing.T) {
 		// given
-		report, err := os.ReadFile("../testdata/valid_report.json")
+		f, err := os.Open("../testdata/valid_report.json")
+		require.NoError(t, err)
+		defer f.Close()
+		report, err := ParseReport(f)
 		require.NoError(t, err)
 		// when
-		vulns, err := getVulnerabilities(report)
+		vulns := getVulnerabilities(report)
 		// then
-		require.NoError(t, err)
 		require.Len(t, vulns, 2)
 		// case where there is no fix available
 		vuln1 := &Vulnerability{
-			ID:       "GO-2025-3547",
-			Summary:  "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
-			MoreInfo: "https://pkg.go.dev/vuln/GO-2025-3547",
-			FoundIn:  "Found in: k8s.io/kubernetes/pkg/features@v1.30.10",
-			FixedIn:  "Fixed in: N/A",
-			Traces:   []string{"main.go:46:2\n", "pkg/cri/containers.go:39:52\n"},
+			ID:           "GO-2025-3547",
+			Summary:      "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+			MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3547",
+			Module:       "k8s.io/kubernetes",
+			FoundIn:      "Found in: k8s.io/kubernetes/pkg/features@v1.30.10",
+			FixedIn:      "Fixed in: N/A",
+			Affected:     []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}},
+			ReviewStatus: ReviewStatusUnreviewed,
+			Traces:       []string{"main.go:46:2\n", "pkg/cri/containers.go:39:52\n"},
+			CallStacks: []CallStack{
+				{Frames: []Frame{
+					{Module: "package", Function: "package.init", Position: "main.go:46:2"},
+					{Module: "k8s.io/kubernetes", Function: "k8s.io/kubernetes/pkg/features.init", Position: "pkg/features/client_adapter.go:17:1"},
+				}},
+				{Frames: []Frame{
+					{Module: "package", Function: "package/pkg/cri.GetContainersPerPID", Position: "pkg/cri/containers.go:39:52"},
+					{Module: "k8s.io/kubernetes", Function: "k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ContainerStatus", Position: "pkg/kubelet/cri/remote/remote_runtime.go:416:32"},
+				}},
+			},
+			Level: ScanLevelSymbol,
 		}
 		// case where the vuln is on go version
 		vuln2 := &Vulnerability{
-			ID:       "GO-2025-3563",
-			Summary:  "Request smuggling due to acceptance of invalid chunked data in net/http",
-			MoreInfo: "https://pkg.go.dev/vuln/GO-2025-3563",
-			FoundIn:  "Found in: net/http/internal@go1.22.12",
-			FixedIn:  "Fixed in: net/http/internal@go1.23.8",
-			Traces:   []string{"pkg/configuration/config.go:95:26\n"},
+			ID:           "GO-2025-3563",
+			Summary:      "Request smuggling due to acceptance of invalid chunked data in net/http",
+			MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3563",
+			Module:       "stdlib",
+			FoundIn:      "Found in: net/http/internal@go1.22.12",
+			FixedIn:      "Fixed in: net/http/internal@go1.23.8",
+			Affected:     []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+			Aliases:      []string{"CVE-2025-22871"},
+			Published:    time.Date(2025, 4, 8, 19, 46, 23, 0, time.UTC),
+			Modified:     time.Date(2025, 4, 8, 19, 46, 23, 0, time.UTC),
+			ReviewStatus: ReviewStatusReviewed,
+			Ranges:       []string{"stdlib: < 1.23.8; >= 1.24.0-0, < 1.24.2"},
+			Traces:       []string{"pkg/configuration/config.go:95:26\n"},
+			CallStacks: []CallStack{
+				{Frames: []Frame{
+					{Module: "package", Function: "package/pkg/configuration.Load", Position: "pkg/configuration/config.go:95:26"},
+					{Module: "stdlib", Function: "net/http.(*body).readLocked", Position: "src/net/http/transfer.go:840:21"},
+					{Module: "stdlib", Function: "net/http/internal.(*chunkedReader).Read", Position: "src/net/http/internal/chunked.go:97:26"},
+				}},
+			},
+			Level: ScanLevelSymbol,
 		}
 		assert.Equal(t, vuln1, vulns[0])
 		assert.Equal(t, vuln2, vulns[1])
 	})
 
-	t.Run("returns error and empty slice for invalid report", func(t *testing.T) {
+	t.Run("get vulnerabilities at package and module levels", func(t *testing.T) {
 		// given
-		report := []byte(`{`)
+		report, err := ParseReport(strings.NewReader(`{"osv":{"id":"GO-2024-2611","summary":"Infinite loop in JSON unmarshaling in google.golang.org/protobuf"}}
+{"osv":{"id":"GO-2025-3563","summary":"Request smuggling due to acceptance of invalid chunked data in net/http"}}
+{"finding":{"osv":"GO-2024-2611","fixed_version":"v1.33.0","trace":[{"module":"google.golang.org/protobuf","version":"v1.32.0"}]}}
+{"finding":{"osv":"GO-2024-2611","fixed_version":"v1.33.0","trace":[{"module":"google.golang.org/protobuf","version":"v1.32.0","package":"google.golang.org/protobuf/encoding/protojson"}]}}
+{"finding":{"osv":"GO-2025-3563","fixed_version":"v1.23.8","trace":[{"module":"stdlib","version":"v1.22.12"}]}}`))
+		require.NoError(t, err)
+		// when
+		vulns := getVulnerabilities(report)
+		// then
+		require.Len(t, vulns, 2)
+		assert.Equal(t, &Vulnerability{
+			ID:       "GO-2024-2611",
+			Summary:  "Infinite loop in JSON unmarshaling in google.golang.org/protobuf",
+			Module:   "google.golang.org/protobuf",
+			FoundIn:  "Found in: google.golang.org/protobuf/encoding/protojson@v1.32.0",
+			FixedIn:  "Fixed in: google.golang.org/protobuf/encoding/protojson@v1.33.0",
+			Affected: []Affected{{Module: "google.golang.org/protobuf", Version: "v1.32.0", FixedVersion: "v1.33.0"}},
+			Traces:   []string{},
+			Level:    ScanLevelPackage,
+		}, vulns[0])
+		assert.Equal(t, &Vulnerability{
+			ID:       "GO-2025-3563",
+			Summary:  "Request smuggling due to acceptance of invalid chunked data in net/http",
+			Module:   "stdlib",
+			FoundIn:  "Found in: stdlib@go1.22.12",
+			FixedIn:  "Fixed in: stdlib@go1.23.8",
+			Affected: []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+			Traces:   []string{},
+			Level:    ScanLevelModule,
+		}, vulns[1])
+	})
+
+	t.Run("returns empty slice for nil report", func(t *testing.T) {
 		// when
-		vulns, err := getVulnerabilities(report)
+		vulns := getVulnerabilities(nil)
 		// then
-		require.Error(t, err)
 		require.Empty(t, vulns)
 	})
 }
 
+func TestClassifyUnreachableVulns(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			{ID: "GO-2025-0001", Level: ScanLevelModule},
+			{ID: "GO-2025-0002", Level: ScanLevelPackage},
+			{ID: "GO-2025-0003", Level: ScanLevelSymbol},
+		}
+	}
+
+	t.Run("symbol level", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyUnreachableVulns(vulns, ScanLevelSymbol, false)
+		// then
+		assert.Equal(t, "the vulnerable module is required but the vulnerable packages are not imported", vulns[0].Informational)
+		assert.Equal(t, "the vulnerable package is imported but the vulnerable symbols are not called", vulns[1].Informational)
+		assert.Empty(t, vulns[2].Informational)
+		assert.Equal(t, []*Vulnerability{vulns[2]}, FailingVulnerabilities(vulns))
+	})
+
+	t.Run("package level", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyUnreachableVulns(vulns, ScanLevelPackage, false)
+		// then
+		assert.NotEmpty(t, vulns[0].Informational)
+		assert.Empty(t, vulns[1].Informational)
+		assert.Empty(t, vulns[2].Informational)
+		assert.Equal(t, vulns[1:], FailingVulnerabilities(vulns))
+	})
+
+	t.Run("fail on unreachable", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyUnreachableVulns(vulns, ScanLevelSymbol, true)
+		// then
+		assert.Equal(t, vulns, FailingVulnerabilities(vulns))
+	})
+}
+
+func TestMergeVulnerabilitiesAtDifferentLevels(t *testing.T) {
+	// given
+	operatorVulns := []*Vulnerability{
+		{
+			ID:       "GO-2025-0001",
+			FoundIn:  "Found in: stdlib@go1.22.10",
+			Affected: []Affected{{Module: "stdlib", Version: "v1.22.10", FixedVersion: "v1.23.8"}},
+			Traces:   []string{},
+			Level:    ScanLevelModule,
+		},
+	}
+	attributeVulnerabilities(Target{ModuleDir: "operator", Module: "example.com/operator"}, operatorVulns)
+	commonVulns := []*Vulnerability{
+		{
+			ID:       "GO-2025-0001",
+			FoundIn:  "Found in: net/http@go1.22.12",
+			Affected: []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}},
+			Traces:   []string{"pkg/client.go:3:4\n"},
+			CallStacks: []CallStack{
+				{Frames: []Frame{
+					{Module: "example.com/common", Function: "example.com/common/pkg.NewClient", Position: "pkg/client.go:3:4"},
+					{Module: "stdlib", Function: "net/http.Get", Position: "src/net/http/client.go:460:6"},
+				}},
+			},
+			Level: ScanLevelSymbol,
+		},
+	}
+	attributeVulnerabilities(Target{ModuleDir: "common", Module: "example.com/common"}, commonVulns)
+	apiVulns := []*Vulnerability{
+		{
+			ID:       "GO-2025-0001",
+			FoundIn:  "Found in: net/http@go1.22.12",
+			Affected: []Affected{{Module: "stdlib", Version: "v1.22.11", FixedVersion: "v1.23.8"}},
+			Traces:   []string{},
+			Level:    ScanLevelPackage,
+		},
+	}
+	attributeVulnerabilities(Target{ModuleDir: "api", Module: "example.com/api"}, apiVulns)
+
+	// when
+	vulns := mergeVulnerabilities(operatorVulns, commonVulns, apiVulns)
+
+	// then
+	require.Len(t, vulns, 1)
+	assert.Equal(t, ScanLevelSymbol, vulns[0].Level)
+	assert.Equal(t, "Found in: net/http@go1.22.12", vulns[0].FoundIn)
+	assert.Equal(t, []string{"example.com/common"}, vulns[0].Modules)
+	assert.Equal(t, []string{"common/pkg/client.go:3:4\n"}, vulns[0].Traces)
+	// the versions affected in all the modules are kept
+	assert.Equal(t, []Affected{
+		{Module: "stdlib", Version: "v1.22.10", FixedVersion: "v1.23.8"},
+		{Module: "stdlib", Version: "v1.22.11", FixedVersion: "v1.23.8"},
+		{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"},
+	}, vulns[0].Affected)
+	// only the positions in the workspace module are relative to the scanned path
+	assert.Equal(t, []CallStack{
+		{Frames: []Frame{
+			{Module: "example.com/common", Function: "example.com/common/pkg.NewClient", Position: "common/pkg/client.go:3:4"},
+			{Module: "stdlib", Function: "net/http.Get", Position: "src/net/http/client.go:460:6"},
+		}},
+	}, vulns[0].CallStacks)
+}
+
+func TestIsTestOnly(t *testing.T) {
+	newFinding := func(pkg, filename string) *Finding {
+		return &Finding{
+			Trace: []Trace{
+				{Module: "stdlib", Package: "net/http", Function: "Get"},
+				{Module: "example.com/operator", Package: pkg, Function: "Test", Position: Position{Filename: filename}},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		findings []*Finding
+		expected bool
+	}{
+		{
+			name:     "called from non-test code",
+			findings: []*Finding{newFinding("example.com/operator/pkg/client", "pkg/client/client.go")},
+			expected: false,
+		},
+		{
+			name:     "called from test file",
+			findings: []*Finding{newFinding("example.com/operator/pkg/client", "pkg/client/client_test.go")},
+			expected: true,
+		},
+		{
+			name:     "called from e2e test package",
+			findings: []*Finding{newFinding("example.com/operator/test/e2e/parallel", "test/e2e/parallel/setup.go")},
+			expected: true,
+		},
+		{
+			name:     "called from package with e2e suffix",
+			findings: []*Finding{newFinding("example.com/operator/pkg/test/e2ehelpers", "pkg/test/e2ehelpers/setup.go")},
+			expected: false,
+		},
+		{
+			name: "called from test and non-test code",
+			findings: []*Finding{
+				newFinding("example.com/operator/pkg/client", "pkg/client/client_test.go"),
+				newFinding("example.com/operator/pkg/client", "pkg/client/client.go"),
+			},
+			expected: false,
+		},
+		{
+			name:     "no finding",
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// when
+			testOnly := isTestOnly(test.findings)
+			// then
+			assert.Equal(t, test.expected, testOnly)
+		})
+	}
+}
+
+func TestAttributeTestOnly(t *testing.T) {
+	// given
+	vulns := []*Vulnerability{
+		{ID: "GO-2025-0001", Level: ScanLevelSymbol, TestOnly: true},
+		{ID: "GO-2025-0002", Level: ScanLevelSymbol},
+		{ID: "GO-2025-0003", Level: ScanLevelSymbol},
+		{ID: "GO-2025-0004", Level: ScanLevelSymbol},
+		{ID: "GO-2025-0005", Level: ScanLevelPackage},
+	}
+	productionVulns := []*Vulnerability{
+		{ID: "GO-2025-0001", Level: ScanLevelSymbol},                 // also called from the production code
+		{ID: "GO-2025-0002", Level: ScanLevelPackage},                // only imported by the production code
+		{ID: "GO-2025-0004", Level: ScanLevelSymbol, TestOnly: true}, // only called from the e2e packages
+		{ID: "GO-2025-0005", Level: ScanLevelPackage},
+	}
+	// when
+	attributeTestOnly(vulns, productionVulns)
+	// then
+	assert.False(t, vulns[0].TestOnly)
+	assert.True(t, vulns[1].TestOnly)
+	assert.True(t, vulns[2].TestOnly) // not found in the production code
+	assert.True(t, vulns[3].TestOnly)
+	assert.False(t, vulns[4].TestOnly)
+}
+
+func TestClassifyTestOnlyVulns(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			{ID: "GO-2025-0001", TestOnly: true},
+			{ID: "GO-2025-0002"},
+			{ID: "GO-2025-0003", TestOnly: true, Informational: "the vulnerable package is imported but the vulnerable symbols are not called"},
+		}
+	}
+
+	t.Run("test-only as informational", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyTestOnlyVulns(vulns, false)
+		// then
+		assert.Equal(t, "the vulnerable symbols are only called from test code", vulns[0].Informational)
+		assert.Empty(t, vulns[1].Informational)
+		assert.Equal(t, "the vulnerable package is imported but the vulnerable symbols are not called", vulns[2].Informational)
+	})
+
+	t.Run("fail on test-only", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyTestOnlyVulns(vulns, true)
+		// then
+		assert.Equal(t, vulns[:2], FailingVulnerabilities(vulns))
+	})
+}
+
 func TestPruneIgnoreVulns(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
@@ -200,6 +481,11 @@ func TestPrintVulnerabilities(t *testing.T) {
 			MoreInfo: "https://pkg.go.dev/vuln/GO-2025-0001",
 			FoundIn:  "Found in: pkg/pkg1@v1.0.0",
 			FixedIn:  "Fixed in: pkg/pkg1@v1.0.1",
+			Affected: []Affected{
+				{Module: "pkg", Version: "v1.0.0", FixedVersion: "v1.0.1"},
+				{Module: "pkg", Version: "v2.0.0"},
+				{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"},
+			},
 			Traces: []string{
 				"file1.go:10:2",
 				"file2.go:21:5",
@@ -212,14 +498,24 @@ func TestPrintVulnerabilities(t *testing.T) {
 			MoreInfo: "https://pkg.go.dev/vuln/GO-2025-0002",
 			FoundIn:  "Found in: pkg/pkg2@v2.0.0",
 			FixedIn:  "Fixed in: pkg/pkg2@v2.0.1",
+			Affected: []Affected{{Module: "pkg", Version: "v2.0.0", FixedVersion: "v2.0.1"}},
 			Traces: []string{
 				"file2.go:21:5",
 			},
 		},
+		{
+			ID:            "GO-2025-0003",
+			Summary:       "summary",
+			MoreInfo:      "https://pkg.go.dev/vuln/GO-2025-0003",
+			FoundIn:       "Found in: pkg/pkg3@v3.0.0",
+			FixedIn:       "Fixed in: pkg/pkg3@v3.0.1",
+			Level:         ScanLevelPackage,
+			Informational: "the vulnerable package is imported but the vulnerable symbols are not called",
+		},
 	}
 
 	// when
-	PrintVulnerabilities(&buf, vulns)
+	PrintVulnerabilities(&buf, vulns, TracesCompact)
 
 	out := buf.String()
 
@@ -232,6 +528,12 @@ func TestPrintVulnerabilities(t *testing.T) {
 	assert.Equal(t, 1, strings.Count(out, "file1.go:10:2"))
 	assert.Contains(t, out, "#1: file1.go:10:2")
 	assert.Contains(t, out, "#2: file2.go:21:5")
+	assert.Contains(t, out, "  Affected versions:\n"+
+		"    pkg@v1.0.0 (fixed in v1.0.1)\n"+
+		"    pkg@v2.0.0 (no fix available)\n"+
+		"    stdlib@go1.22.12 (fixed in go1.23.8)\n")
+	// not shown when there is a single affected version
+	assert.Equal(t, 1, strings.Count(out, "Affected versions:"))
 
 	assert.Contains(t, out, "Vulnerability #2: GO-2025-0002")
 	assert.Contains(t, out, "summary")
@@ -239,4 +541,412 @@ func TestPrintVulnerabilities(t *testing.T) {
 	assert.Contains(t, out, "Found in: pkg/pkg2@v2.0.0")
 	assert.Contains(t, out, "Fixed in: pkg/pkg2@v2.0.1")
 	assert.Contains(t, out, "#1: file2.go:21:5")
+
+	// no exclusion entry for the informational vulnerability, which does not fail the scan
+	assert.Contains(t, out, "Vulnerability #3: GO-2025-0003")
+	assert.Equal(t, 2, strings.Count(out, "add the following entry in your exclusion file"))
+	assert.Contains(t, out, "  - id: GO-2025-0002\n")
+	assert.NotContains(t, out, "  - id: GO-2025-0003\n")
+}
+
+func TestPrintVulnerabilityTraces(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			{
+				ID:     "GO-2025-3547",
+				Traces: []string{"pkg/cri/containers.go:39:52\n", "pkg/cri/containers.go:39:52\n"},
+				CallStacks: []CallStack{
+					{Frames: []Frame{
+						{Function: "example.com/operator/pkg/cri.GetContainersPerPID", Position: "pkg/cri/containers.go:39:52"},
+						{Function: "k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ListContainers", Position: "pkg/kubelet/cri/remote/remote_runtime.go:394:32"},
+					}},
+					{Frames: []Frame{
+						{Function: "example.com/operator/pkg/cri.GetContainersPerPID", Position: "pkg/cri/containers.go:39:52"},
+						{Function: "k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ContainerStatus", Position: "pkg/kubelet/cri/remote/remote_runtime.go:416:32"},
+					}},
+				},
+			},
+			{
+				ID:     "GO-2025-3563",
+				Traces: []string{"bin/manager: net/http/internal.(*chunkedReader).Read\n"},
+				CallStacks: []CallStack{
+					{Binary: "bin/manager", Frames: []Frame{
+						{Function: "net/http/internal.(*chunkedReader).Read"},
+					}},
+				},
+			},
+		}
+	}
+
+	t.Run("full", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintVulnerabilities(&buf, newVulns(), TracesFull)
+		// then
+		out := buf.String()
+		assert.Contains(t, out, "  Call stacks found:\n"+
+			"    #1: example.com/operator/pkg/cri.GetContainersPerPID (pkg/cri/containers.go:39:52)\n"+
+			"        k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ListContainers (pkg/kubelet/cri/remote/remote_runtime.go:394:32)\n"+
+			"    #2: example.com/operator/pkg/cri.GetContainersPerPID (pkg/cri/containers.go:39:52)\n"+
+			"        k8s.io/kubernetes/pkg/kubelet/cri/remote.(*remoteRuntimeService).ContainerStatus (pkg/kubelet/cri/remote/remote_runtime.go:416:32)\n")
+		assert.Contains(t, out, "    #1: bin/manager: net/http/internal.(*chunkedReader).Read\n")
+		assert.NotContains(t, out, "Example traces found:")
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintVulnerabilities(&buf, newVulns(), TracesCompact)
+		// then
+		out := buf.String()
+		assert.Contains(t, out, "  Example traces found:\n    #1: pkg/cri/containers.go:39:52\n\n")
+		assert.NotContains(t, out, "Call stacks found:")
+		assert.NotContains(t, out, "ListContainers")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		// given
+		var buf bytes.Buffer
+		// when
+		PrintVulnerabilities(&buf, newVulns(), TracesNone)
+		// then
+		out := buf.String()
+		assert.NotContains(t, out, "traces found:")
+		assert.NotContains(t, out, "Call stacks found:")
+		assert.NotContains(t, out, "pkg/cri/containers.go")
+	})
+}
+
+func TestGetRanges(t *testing.T) {
+	newAffected := func(module string, events ...RangeEvent) OSVAffected {
+		return OSVAffected{
+			Module: OSVModule{Path: module, Ecosystem: "Go"},
+			Ranges: []Range{{Type: "SEMVER", Events: events}},
+		}
+	}
+	tests := []struct {
+		name     string
+		affected []OSVAffected
+		expected []string
+	}{
+		{
+			name:     "no affected module",
+			expected: nil,
+		},
+		{
+			name:     "fixed",
+			affected: []OSVAffected{newAffected("golang.org/x/net", RangeEvent{Introduced: "0"}, RangeEvent{Fixed: "0.38.0"})},
+			expected: []string{"golang.org/x/net: < 0.38.0"},
+		},
+		{
+			name: "multiple intervals",
+			affected: []OSVAffected{newAffected("stdlib",
+				RangeEvent{Introduced: "0"}, RangeEvent{Fixed: "1.23.8"},
+				RangeEvent{Introduced: "1.24.0-0"}, RangeEvent{Fixed: "1.24.2"},
+			)},
+			expected: []string{"stdlib: < 1.23.8; >= 1.24.0-0, < 1.24.2"},
+		},
+		{
+			name: "not fixed",
+			affected: []OSVAffected{
+				newAffected("k8s.io/kubernetes", RangeEvent{Introduced: "0"}),
+				newAffected("k8s.io/apiserver", RangeEvent{Introduced: "0.30.0"}),
+			},
+			expected: []string{"k8s.io/kubernetes: all versions", "k8s.io/apiserver: >= 0.30.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// when
+			got := getRanges(test.affected)
+			// then
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestClassifyUnreviewedVulns(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			{ID: "GO-2025-0001", ReviewStatus: ReviewStatusUnreviewed, Published: time.Now().Add(-2 * 24 * time.Hour)},
+			{ID: "GO-2025-0002", ReviewStatus: ReviewStatusUnreviewed, Published: time.Now().Add(-10 * 24 * time.Hour)},
+			{ID: "GO-2025-0003", ReviewStatus: ReviewStatusReviewed, Published: time.Now().Add(-2 * 24 * time.Hour)},
+			// unknown publication date
+			{ID: "GO-2025-0004", ReviewStatus: ReviewStatusUnreviewed},
+		}
+	}
+
+	t.Run("warn during the first days", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyUnreviewedVulns(vulns, 7)
+		// then
+		assert.Equal(t, "the advisory is unreviewed and was published less than 7 days ago", vulns[0].Informational)
+		assert.Equal(t, []*Vulnerability{vulns[1], vulns[2], vulns[3]}, FailingVulnerabilities(vulns))
+	})
+
+	t.Run("always fail", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyUnreviewedVulns(vulns, 0)
+		// then
+		assert.Equal(t, vulns, FailingVulnerabilities(vulns))
+	})
+}
+
+func TestPrintVulnerabilityMetadata(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	vulns := []*Vulnerability{
+		{
+			ID:           "GO-2025-3563",
+			Summary:      "Request smuggling due to acceptance of invalid chunked data in net/http",
+			MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3563",
+			Aliases:      []string{"CVE-2025-22871"},
+			Published:    time.Date(2025, 4, 8, 19, 46, 23, 0, time.UTC),
+			Modified:     time.Date(2025, 4, 10, 9, 0, 0, 0, time.UTC),
+			ReviewStatus: ReviewStatusReviewed,
+			Ranges:       []string{"stdlib: < 1.23.8; >= 1.24.0-0, < 1.24.2"},
+			Fingerprints: []string{"0f5a5c2e8d1b7a43", "9c1e44d07b3a6f52"},
+		},
+		{
+			ID:           "GO-2025-3547",
+			Summary:      "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+			MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3547",
+			ReviewStatus: ReviewStatusUnreviewed,
+		},
+	}
+
+	// when
+	PrintVulnerabilities(&buf, vulns, TracesCompact)
+
+	// then
+	out := buf.String()
+	assert.Contains(t, out, "  More info: https://pkg.go.dev/vuln/GO-2025-3563\n"+
+		"  Aliases: CVE-2025-22871\n"+
+		"  Published: 2025-04-08 (modified: 2025-04-10)\n"+
+		"  Review status: REVIEWED\n"+
+		"  Affected ranges:\n"+
+		"    stdlib: < 1.23.8; >= 1.24.0-0, < 1.24.2\n")
+	assert.Contains(t, out, "  More info: https://pkg.go.dev/vuln/GO-2025-3547\n"+
+		"  Review status: UNREVIEWED\n")
+	assert.Equal(t, 1, strings.Count(out, "Published:"))
+	assert.Contains(t, out, "  Fingerprints: 0f5a5c2e8d1b7a43, 9c1e44d07b3a6f52\n")
+	assert.Equal(t, 1, strings.Count(out, "Fingerprints:"))
+}
+
+func TestEnrichSeverity(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			{ID: "GO-2025-3487", Aliases: []string{"CVE-2025-22869"}},
+			{ID: "GO-2025-3547"},
+			{ID: "GO-2025-3563", Aliases: []string{"CVE-2025-22871"}},
+			{ID: "GO-2025-3595", Aliases: []string{"CVE-2025-22872", "GHSA-vvgc-356p-c3xw"}},
+		}
+	}
+	data := severity.Data{
+		"CVE-2025-22869":      {Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", Score: 7.5},
+		"CVE-2025-22871":      {Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", Score: 7.5},
+		"GHSA-vvgc-356p-c3xw": {Score: 4.2},
+		"GO-2025-3547":        {Score: 2.1},
+	}
+
+	t.Run("sorted by decreasing score", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		enrichSeverity(vulns, data)
+		// then
+		ids := make([]string, 0, len(vulns))
+		for _, v := range vulns {
+			require.NotNil(t, v.Severity, v.ID)
+			ids = append(ids, v.ID)
+		}
+		// same scores are sorted by ID
+		assert.Equal(t, []string{"GO-2025-3487", "GO-2025-3563", "GO-2025-3595", "GO-2025-3547"}, ids)
+		assert.InDelta(t, 4.2, vulns[2].Severity.Score, 0.0001)
+	})
+
+	t.Run("unknown severities are last", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		enrichSeverity(vulns, severity.Data{"CVE-2025-22872": {Score: 6.5}})
+		// then
+		assert.Equal(t, "GO-2025-3595", vulns[0].ID)
+		for _, v := range vulns[1:] {
+			assert.Nil(t, v.Severity, v.ID)
+		}
+		assert.Equal(t, []string{"GO-2025-3487", "GO-2025-3547", "GO-2025-3563"}, []string{vulns[1].ID, vulns[2].ID, vulns[3].ID})
+	})
+
+	t.Run("no severity data", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		enrichSeverity(vulns, nil)
+		// then
+		assert.Equal(t, newVulns(), vulns)
+	})
+}
+
+func TestClassifyMinSeverityVulns(t *testing.T) {
+	// given
+	vulns := []*Vulnerability{
+		{ID: "GO-2025-0001", Severity: &severity.Severity{Score: 9.8}},
+		{ID: "GO-2025-0002", Severity: &severity.Severity{Score: 5.9}},
+		// unknown severity
+		{ID: "GO-2025-0003"},
+		// already informational
+		{ID: "GO-2025-0004", Severity: &severity.Severity{Score: 3.1}, Informational: "the vulnerable symbols are only called from test code"},
+	}
+	// when
+	classifyMinSeverityVulns(vulns, 7)
+	// then
+	assert.Equal(t, "the severity is below the minimum severity (7.0)", vulns[1].Informational)
+	assert.Equal(t, "the vulnerable symbols are only called from test code", vulns[3].Informational)
+	assert.Equal(t, []*Vulnerability{vulns[0], vulns[2]}, FailingVulnerabilities(vulns))
+}
+
+func TestPrintVulnerabilitySeverity(t *testing.T) {
+	// given
+	var buf bytes.Buffer
+	vulns := []*Vulnerability{
+		{
+			ID:           "GO-2025-3563",
+			Summary:      "Request smuggling due to acceptance of invalid chunked data in net/http",
+			MoreInfo:     "https://pkg.go.dev/vuln/GO-2025-3563",
+			ReviewStatus: ReviewStatusReviewed,
+			Severity:     &severity.Severity{Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", Score: 7.5},
+		},
+		{
+			ID:       "GO-2025-3547",
+			Summary:  "Kubernetes kube-apiserver Vulnerable to Race Condition in k8s.io/kubernetes",
+			MoreInfo: "https://pkg.go.dev/vuln/GO-2025-3547",
+		},
+	}
+
+	// when
+	PrintVulnerabilities(&buf, vulns, TracesCompact)
+
+	// then
+	out := buf.String()
+	assert.Contains(t, out, "  Review status: REVIEWED\n"+
+		"  Severity: 7.5 (High) CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N\n")
+	assert.Equal(t, 1, strings.Count(out, "Severity:"))
+}
+
+func TestClassifyBaselineVulns(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			// same findings as in the baseline
+			{ID: "GO-2025-0001", Fingerprints: []string{"0000000000000001", "0000000000000002"}},
+			// fewer findings than in the baseline
+			{ID: "GO-2025-0002", Fingerprints: []string{"0000000000000003"}},
+			// new call site
+			{ID: "GO-2025-0003", Fingerprints: []string{"0000000000000005", "0000000000000006"}},
+			// new vulnerability
+			{ID: "GO-2025-0004", Fingerprints: []string{"0000000000000007"}},
+		}
+	}
+	baseline := []*Vulnerability{
+		{ID: "GO-2025-0001", Fingerprints: []string{"0000000000000001", "0000000000000002"}},
+		{ID: "GO-2025-0002", Fingerprints: []string{"0000000000000003", "0000000000000004"}},
+		{ID: "GO-2025-0003", Fingerprints: []string{"0000000000000005"}},
+		// fixed since the baseline
+		{ID: "GO-2025-0005", Fingerprints: []string{"0000000000000008"}},
+	}
+
+	t.Run("only new vulnerabilities and call sites fail", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyBaselineVulns(vulns, baseline)
+		// then
+		assert.Equal(t, "the vulnerability was already found in the baseline", vulns[0].Informational)
+		assert.Equal(t, "the vulnerability was already found in the baseline", vulns[1].Informational)
+		assert.Equal(t, []*Vulnerability{vulns[2], vulns[3]}, FailingVulnerabilities(vulns))
+	})
+
+	t.Run("empty baseline", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyBaselineVulns(vulns, []*Vulnerability{})
+		// then
+		assert.Equal(t, vulns, FailingVulnerabilities(vulns))
+	})
+
+	t.Run("no baseline", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyBaselineVulns(vulns, nil)
+		// then
+		assert.Equal(t, vulns, FailingVulnerabilities(vulns))
+	})
+}
+
+func TestClassifyFailOnVulns(t *testing.T) {
+	newVulns := func() []*Vulnerability {
+		return []*Vulnerability{
+			// fixable stdlib vulnerability, called
+			{ID: "GO-2025-0001", Module: "stdlib", Level: ScanLevelSymbol, Affected: []Affected{{Module: "stdlib", Version: "v1.22.12", FixedVersion: "v1.23.8"}}},
+			// fixable third-party vulnerability, called
+			{ID: "GO-2025-0002", Module: "golang.org/x/net", Level: ScanLevelSymbol, Affected: []Affected{{Module: "golang.org/x/net", Version: "v0.33.0", FixedVersion: "v0.38.0"}}},
+			// unfixable third-party vulnerability, imported
+			{ID: "GO-2025-0003", Module: "k8s.io/kubernetes", Level: ScanLevelPackage, Affected: []Affected{{Module: "k8s.io/kubernetes", Version: "v1.30.10"}}},
+			// third-party vulnerability which is not fixed in one of its affected versions
+			{ID: "GO-2025-0004", Module: "golang.org/x/crypto", Level: ScanLevelSymbol, Affected: []Affected{
+				{Module: "golang.org/x/crypto", Version: "v0.31.0", FixedVersion: "v0.35.0"},
+				{Module: "golang.org/x/crypto", Version: "v0.36.0"},
+			}},
+		}
+	}
+
+	tests := map[string]struct {
+		selectors []string
+		failing   []string
+	}{
+		"no selectors":               {selectors: nil, failing: []string{"GO-2025-0001", "GO-2025-0002", "GO-2025-0003", "GO-2025-0004"}},
+		"all":                        {selectors: []string{FailOnAll, FailOnStdLib}, failing: []string{"GO-2025-0001", "GO-2025-0002", "GO-2025-0003", "GO-2025-0004"}},
+		"stdlib":                     {selectors: []string{FailOnStdLib}, failing: []string{"GO-2025-0001"}},
+		"third-party":                {selectors: []string{FailOnThirdParty}, failing: []string{"GO-2025-0002", "GO-2025-0003", "GO-2025-0004"}},
+		"stdlib or third-party":      {selectors: []string{FailOnStdLib, FailOnThirdParty}, failing: []string{"GO-2025-0001", "GO-2025-0002", "GO-2025-0003", "GO-2025-0004"}},
+		"fixable":                    {selectors: []string{FailOnFixable}, failing: []string{"GO-2025-0001", "GO-2025-0002"}},
+		"unfixable":                  {selectors: []string{FailOnUnfixable}, failing: []string{"GO-2025-0003", "GO-2025-0004"}},
+		"fixable third-party":        {selectors: []string{FailOnThirdParty, FailOnFixable}, failing: []string{"GO-2025-0002"}},
+		"reachable":                  {selectors: []string{FailOnReachable}, failing: []string{"GO-2025-0001", "GO-2025-0002", "GO-2025-0004"}},
+		"reachable third-party":      {selectors: []string{FailOnReachable, FailOnThirdParty}, failing: []string{"GO-2025-0002", "GO-2025-0004"}},
+		"reachable unfixable stdlib": {selectors: []string{FailOnReachable, FailOnStdLib, FailOnUnfixable}, failing: []string{}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			vulns := newVulns()
+			// when
+			classifyFailOnVulns(vulns, test.selectors)
+			// then
+			failing := []string{}
+			for _, v := range FailingVulnerabilities(vulns) {
+				failing = append(failing, v.ID)
+			}
+			assert.Equal(t, test.failing, failing)
+		})
+	}
+
+	t.Run("informational reason", func(t *testing.T) {
+		// given
+		vulns := newVulns()
+		// when
+		classifyFailOnVulns(vulns, []string{FailOnThirdParty, FailOnFixable})
+		// then
+		assert.Equal(t, "the vulnerability does not match the fail-on selectors (third-party, fixable)", vulns[0].Informational)
+	})
 }
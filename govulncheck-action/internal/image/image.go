@@ -0,0 +1,324 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"debug/buildinfo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// whiteoutPrefix is the prefix of the files which mark the deletion of a file from a lower layer
+	// see https://github.com/opencontainers/image-spec/blob/main/layer.md#whiteouts
+	whiteoutPrefix = ".wh."
+	// opaqueWhiteout is the file which marks the deletion of all the files of its directory from the lower layers
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+// Extract extracts the Go binaries of the image stored in the given archive into the given directory,
+// and returns the directory of the extracted filesystem along with the paths of the Go binaries in the image
+// (eg: `/usr/local/bin/manager`).
+// The archive can be a tarball produced by `docker save` or `podman save` (in the `docker-archive` or `oci-archive` format),
+// or a directory with an OCI image layout. The layers are applied in order, including their whiteouts,
+// so that only the binaries which are actually in the final image are returned.
+func Extract(archive, dir string) (string, []string, error) {
+	info, err := os.Stat(archive)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid image archive '%s': %w", archive, err)
+	}
+	layout := archive
+	if !info.IsDir() {
+		layout = filepath.Join(dir, "archive")
+		if err := extractArchive(archive, layout); err != nil {
+			return "", nil, err
+		}
+	}
+	layers, err := getLayers(layout)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid image archive '%s': %w", archive, err)
+	}
+	rootfs := filepath.Join(dir, "rootfs")
+	binaries := make(map[string]bool)
+	for _, l := range layers {
+		if err := extractLayer(l, rootfs, binaries); err != nil {
+			return "", nil, err
+		}
+	}
+	result := make([]string, 0, len(binaries))
+	for b := range binaries {
+		result = append(result, b)
+	}
+	slices.Sort(result)
+	return rootfs, result, nil
+}
+
+// extractArchive extracts the files of the given tarball (optionally gzipped) into the given directory
+func extractArchive(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open image archive '%s': %w", archive, err)
+	}
+	defer f.Close()
+	r, err := decompress(f)
+	if err != nil {
+		return fmt.Errorf("failed to read image archive '%s': %w", archive, err)
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read image archive '%s': %w", archive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			// directories are created along with the files, and the layouts do not rely on links
+			continue
+		}
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(path.Clean("/"+hdr.Name))), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// getLayers returns the paths to the layers of the image in the given directory, from the lowest to the highest,
+// based on the `index.json` file of an OCI image layout or on the `manifest.json` file of a `docker save` archive
+func getLayers(layout string) ([]string, error) {
+	if index, err := os.ReadFile(filepath.Join(layout, "index.json")); err == nil {
+		return getOCILayers(layout, index)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	manifest, err := os.ReadFile(filepath.Join(layout, "manifest.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("no `index.json` nor `manifest.json` file")
+	} else if err != nil {
+		return nil, err
+	}
+	return getDockerLayers(layout, manifest)
+}
+
+// descriptor is a reference to a blob of an OCI image layout
+// see https://github.com/opencontainers/image-spec/blob/main/descriptor.md
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// getOCILayers returns the paths to the layers of the single image referenced by the given index
+// see https://github.com/opencontainers/image-spec/blob/main/image-layout.md
+func getOCILayers(layout string, index []byte) ([]string, error) {
+	for {
+		var idx struct {
+			Manifests []descriptor `json:"manifests"`
+		}
+		if err := json.Unmarshal(index, &idx); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal image index: %w", err)
+		}
+		if len(idx.Manifests) != 1 {
+			return nil, fmt.Errorf("expected a single image, found %d", len(idx.Manifests))
+		}
+		data, err := readBlob(layout, idx.Manifests[0].Digest)
+		if err != nil {
+			return nil, err
+		}
+		if idx.Manifests[0].MediaType == "application/vnd.oci.image.index.v1+json" {
+			// nested index
+			index = data
+			continue
+		}
+		var manifest struct {
+			Layers []descriptor `json:"layers"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal image manifest: %w", err)
+		}
+		layers := make([]string, 0, len(manifest.Layers))
+		for _, l := range manifest.Layers {
+			p, err := blobPath(layout, l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, p)
+		}
+		return layers, nil
+	}
+}
+
+// getDockerLayers returns the paths to the layers of the single image in the `manifest.json` file of a `docker save` archive
+func getDockerLayers(layout string, data []byte) ([]string, error) {
+	var manifest []struct {
+		Layers []string `json:"Layers"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal `manifest.json` file: %w", err)
+	}
+	if len(manifest) != 1 {
+		return nil, fmt.Errorf("expected a single image, found %d", len(manifest))
+	}
+	layers := make([]string, 0, len(manifest[0].Layers))
+	for _, l := range manifest[0].Layers {
+		layers = append(layers, filepath.Join(layout, filepath.FromSlash(path.Clean("/"+l))))
+	}
+	return layers, nil
+}
+
+// blobPath returns the path to the blob with the given digest (eg: `sha256:abc...`) in the OCI image layout
+func blobPath(layout, digest string) (string, error) {
+	alg, hex, found := strings.Cut(digest, ":")
+	if !found || alg == "" || hex == "" || strings.ContainsAny(digest, `/\.`) {
+		return "", fmt.Errorf("invalid digest: '%s'", digest)
+	}
+	return filepath.Join(layout, "blobs", alg, hex), nil
+}
+
+func readBlob(layout, digest string) ([]byte, error) {
+	p, err := blobPath(layout, digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// extractLayer applies the given layer to the filesystem in the rootfs directory:
+// the Go binaries of the layer are extracted and added to the given set,
+// while the files which are deleted or replaced by the layer are removed from the set
+func extractLayer(layer, rootfs string, binaries map[string]bool) error {
+	f, err := os.Open(layer)
+	if err != nil {
+		return fmt.Errorf("failed to open layer: %w", err)
+	}
+	defer f.Close()
+	r, err := decompress(f)
+	if err != nil {
+		return fmt.Errorf("failed to read layer '%s': %w", filepath.Base(layer), err)
+	}
+	// files added by this layer, which are not affected by its opaque whiteouts
+	added := make(map[string]bool)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read layer '%s': %w", filepath.Base(layer), err)
+		}
+		name := path.Clean("/" + hdr.Name)
+		base := path.Base(name)
+		switch {
+		case base == opaqueWhiteout:
+			remove(rootfs, binaries, path.Dir(name), func(b string) bool {
+				return !added[b]
+			})
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			remove(rootfs, binaries, path.Join(path.Dir(name), strings.TrimPrefix(base, whiteoutPrefix)), nil)
+			continue
+		}
+		added[name] = true
+		if hdr.Typeflag == tar.TypeDir {
+			// the files of the directory in the lower layers are kept
+			continue
+		}
+		// any other entry replaces the file of the lower layers
+		if binaries[name] {
+			delete(binaries, name)
+			os.Remove(filepath.Join(rootfs, filepath.FromSlash(name)))
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Mode&0o111 == 0 {
+			continue
+		}
+		isGo, err := extractBinary(filepath.Join(rootfs, filepath.FromSlash(name)), tr)
+		if err != nil {
+			return err
+		}
+		if isGo {
+			binaries[name] = true
+		}
+	}
+}
+
+// remove removes the file with the given name (and all the files in it if it is a directory) from the set of binaries,
+// if they match the given filter (if any)
+func remove(rootfs string, binaries map[string]bool, name string, filter func(string) bool) {
+	for b := range binaries {
+		if b != name && !strings.HasPrefix(b, strings.TrimSuffix(name, "/")+"/") {
+			continue
+		}
+		if filter != nil && !filter(b) {
+			continue
+		}
+		delete(binaries, b)
+		os.Remove(filepath.Join(rootfs, filepath.FromSlash(b)))
+	}
+}
+
+// executableMagics are the magic numbers of the executable formats supported by Go
+var executableMagics = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+}
+
+// extractBinary writes the executable to the given path and returns true if it is a Go binary
+// (otherwise, the file is not kept)
+func extractBinary(dest string, r io.Reader) (bool, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	if !slices.ContainsFunc(executableMagics, func(m []byte) bool {
+		return bytes.HasPrefix(magic, m)
+	}) {
+		// eg: shell scripts
+		return false, nil
+	}
+	if err := writeFile(dest, br); err != nil {
+		return false, err
+	}
+	if _, err := buildinfo.ReadFile(dest); err != nil {
+		// not a Go binary
+		os.Remove(dest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// writeFile writes the content of the reader to the given path, creating the parent directories if needed
+func writeFile(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create '%s': %w", dest, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write '%s': %w", dest, err)
+	}
+	return f.Close()
+}
+
+// decompress returns a reader of the decompressed content of a (gzipped or uncompressed) tarball
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return nil, errors.New("zstd compression is not supported")
+	default:
+		return br, nil
+	}
+}
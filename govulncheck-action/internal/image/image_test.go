@@ -0,0 +1,269 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/image"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+
+	// the test binary itself is a Go binary
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	goBinary, err := os.ReadFile(executable)
+	require.NoError(t, err)
+
+	t.Run("docker archive", func(t *testing.T) {
+		// given
+		base := newLayer(t,
+			entry{name: "usr/", dir: true},
+			entry{name: "usr/local/bin/manager", mode: 0o755, content: goBinary},
+			entry{name: "usr/local/bin/old", mode: 0o755, content: goBinary},
+			entry{name: "usr/local/bin/config.json", mode: 0o644, content: goBinary}, // not executable
+			entry{name: "usr/bin/entrypoint.sh", mode: 0o755, content: []byte("#!/bin/sh\n")},
+			entry{name: "usr/bin/ls", mode: 0o755, content: []byte("\x7fELF not a Go binary")},
+		)
+		top := gzipped(t, newLayer(t,
+			entry{name: "usr/", dir: true},
+			entry{name: "usr/local/bin/.wh.old"},
+			entry{name: "opt/tool", mode: 0o755, content: goBinary},
+		))
+		archive := writeTar(t, filepath.Join(t.TempDir(), "operator.tar"), map[string][]byte{
+			"manifest.json":  []byte(`[{"Config":"config.json","RepoTags":["quay.io/codeready-toolchain/operator:latest"],"Layers":["1/layer.tar","2/layer.tar"]}]`),
+			"config.json":    []byte(`{}`),
+			"1/layer.tar":    base,
+			"2/layer.tar":    top,
+			"../escape.json": []byte(`{}`),
+		})
+		dir := t.TempDir()
+
+		// when
+		rootfs, binaries, err := image.Extract(archive, dir)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/opt/tool", "/usr/local/bin/manager"}, binaries)
+		assert.FileExists(t, filepath.Join(rootfs, "usr", "local", "bin", "manager"))
+		assert.FileExists(t, filepath.Join(rootfs, "opt", "tool"))
+		assert.NoFileExists(t, filepath.Join(rootfs, "usr", "local", "bin", "old"))
+		assert.NoFileExists(t, filepath.Join(rootfs, "usr", "bin", "ls"))
+		assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escape.json"))
+	})
+
+	t.Run("OCI layout directory with opaque whiteout", func(t *testing.T) {
+		// given
+		layout := newOCILayout(t, t.TempDir(),
+			newLayer(t,
+				entry{name: "app/a", mode: 0o755, content: goBinary},
+				entry{name: "app/b", mode: 0o755, content: goBinary},
+			),
+			gzipped(t, newLayer(t,
+				entry{name: "app/c", mode: 0o755, content: goBinary},
+				entry{name: "app/.wh..wh..opq"},
+			)),
+		)
+
+		// when
+		_, binaries, err := image.Extract(layout, t.TempDir())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/app/c"}, binaries)
+	})
+
+	t.Run("OCI archive", func(t *testing.T) {
+		// given
+		layout := newOCILayout(t, t.TempDir(),
+			gzipped(t, newLayer(t,
+				entry{name: "manager", mode: 0o755, content: goBinary},
+			)),
+		)
+		files := map[string][]byte{}
+		err := filepath.WalkDir(layout, func(p string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(layout, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)], err = os.ReadFile(p)
+			return err
+		})
+		require.NoError(t, err)
+		archive := writeTar(t, filepath.Join(t.TempDir(), "operator.tar"), files)
+
+		// when
+		_, binaries, err := image.Extract(archive, t.TempDir())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/manager"}, binaries)
+	})
+
+	t.Run("no Go binary", func(t *testing.T) {
+		// given
+		layout := newOCILayout(t, t.TempDir(),
+			newLayer(t, entry{name: "bin/sh", mode: 0o755, content: []byte("\x7fELF not a Go binary")}),
+		)
+
+		// when
+		_, binaries, err := image.Extract(layout, t.TempDir())
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, binaries)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+
+		t.Run("missing archive", func(t *testing.T) {
+			// when
+			_, _, err := image.Extract(filepath.Join(t.TempDir(), "missing.tar"), t.TempDir())
+			// then
+			require.ErrorContains(t, err, "invalid image archive")
+		})
+
+		t.Run("not an image", func(t *testing.T) {
+			// given
+			archive := writeTar(t, filepath.Join(t.TempDir(), "operator.tar"), map[string][]byte{
+				"README.md": []byte("not an image"),
+			})
+			// when
+			_, _, err := image.Extract(archive, t.TempDir())
+			// then
+			require.EqualError(t, err, "invalid image archive '"+archive+"': no `index.json` nor `manifest.json` file")
+		})
+
+		t.Run("multiple images", func(t *testing.T) {
+			// given
+			archive := writeTar(t, filepath.Join(t.TempDir(), "operator.tar"), map[string][]byte{
+				"manifest.json": []byte(`[{"Layers":["1/layer.tar"]},{"Layers":["2/layer.tar"]}]`),
+			})
+			// when
+			_, _, err := image.Extract(archive, t.TempDir())
+			// then
+			require.EqualError(t, err, "invalid image archive '"+archive+"': expected a single image, found 2")
+		})
+
+		t.Run("invalid digest", func(t *testing.T) {
+			// given
+			layout := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(layout, "index.json"), []byte(`{"manifests":[{"digest":"sha256:../../etc/passwd"}]}`), 0o600))
+			// when
+			_, _, err := image.Extract(layout, t.TempDir())
+			// then
+			require.EqualError(t, err, "invalid image archive '"+layout+"': invalid digest: 'sha256:../../etc/passwd'")
+		})
+	})
+}
+
+type entry struct {
+	name    string
+	dir     bool
+	mode    int64
+	content []byte
+}
+
+// newLayer returns an uncompressed layer with the given entries
+func newLayer(t *testing.T, entries ...entry) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:     e.name,
+			Mode:     e.mode,
+			Size:     int64(len(e.content)),
+			Typeflag: tar.TypeReg,
+		}
+		if e.dir {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0o755
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write(e.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	_, err := gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+// writeTar writes a tarball with the given files at the given path
+func writeTar(t *testing.T, path string, files map[string][]byte) string {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return path
+}
+
+// newOCILayout writes an OCI image layout with the given layers in the given directory
+func newOCILayout(t *testing.T, dir string, layers ...[]byte) string {
+	writeBlob := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", digest), data, 0o600))
+		return "sha256:" + digest
+	}
+	type descriptor struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	}
+	manifest := struct {
+		Layers []descriptor `json:"layers"`
+	}{}
+	for _, l := range layers {
+		manifest.Layers = append(manifest.Layers, descriptor{
+			MediaType: "application/vnd.oci.image.layer.v1.tar",
+			Digest:    writeBlob(l),
+		})
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	index := struct {
+		Manifests []descriptor `json:"manifests"`
+	}{
+		Manifests: []descriptor{{
+			MediaType: "application/vnd.oci.image.manifest.v1+json",
+			Digest:    writeBlob(data),
+		}},
+	}
+	data, err = json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o600))
+	return dir
+}
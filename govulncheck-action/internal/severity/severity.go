@@ -0,0 +1,222 @@
+package severity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TypeCVSSv3 is the type of the CVSS v3.x vectors, in the OSV format
+	TypeCVSSv3 = "CVSS_V3"
+	// TypeCVSSv4 is the type of the CVSS v4.0 vectors, in the OSV format
+	TypeCVSSv4 = "CVSS_V4"
+)
+
+// Severity is the CVSS severity of a vulnerability
+type Severity struct {
+	// Vector is the CVSS vector (eg: `CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N`, empty if only the score is known)
+	Vector string `json:"vector,omitempty"`
+	// Score is the CVSS base score, between 0 and 10
+	Score float64 `json:"score"`
+}
+
+// Rating returns the qualitative rating of the score: `None`, `Low`, `Medium`, `High` or `Critical`
+// see https://www.first.org/cvss/v3.1/specification-document#Qualitative-Severity-Rating-Scale
+func (s Severity) Rating() string {
+	switch {
+	case s.Score >= 9:
+		return "Critical"
+	case s.Score >= 7:
+		return "High"
+	case s.Score >= 4:
+		return "Medium"
+	case s.Score > 0:
+		return "Low"
+	default:
+		return "None"
+	}
+}
+
+func (s Severity) String() string {
+	if s.Vector == "" {
+		return fmt.Sprintf("%.1f (%s)", s.Score, s.Rating())
+	}
+	return fmt.Sprintf("%.1f (%s) %s", s.Score, s.Rating(), s.Vector)
+}
+
+// Data contains the severities of the vulnerabilities, keyed by alias (eg: `CVE-2025-22871`, `GHSA-...` or `GO-...`)
+type Data map[string]Severity
+
+// entry is an entry of a severity data file, with OSV-style severities and/or an NVD-style base score
+// example:
+//
+//	"CVE-2025-22871": {
+//	  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N"}],
+//	  "score": 7.5
+//	}
+type entry struct {
+	Severity []struct {
+		Type string `json:"type"`
+		// Score is the CVSS vector (as named in the OSV format)
+		Score string `json:"score"`
+	} `json:"severity"`
+	// Score is the CVSS base score (computed from the CVSS v3 vector if missing)
+	Score *float64 `json:"score"`
+}
+
+// Load reads the severity data file, which is a JSON object keyed by alias.
+// The base score of an entry is computed from its CVSS v3 vector when it is not specified.
+func Load(path string) (Data, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity data: %w", err)
+	}
+	var entries map[string]entry
+	if err := json.Unmarshal(contents, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal severity data '%s': %w", path, err)
+	}
+	data := make(Data, len(entries))
+	for alias, e := range entries {
+		s, err := e.severity()
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity data for '%s': %w", alias, err)
+		}
+		data[alias] = s
+	}
+	return data, nil
+}
+
+// severity returns the severity of the entry, preferring the CVSS v3 vectors whose score can be computed
+func (e entry) severity() (Severity, error) {
+	var s Severity
+	for _, sev := range e.Severity {
+		if sev.Type == TypeCVSSv3 || (sev.Type == TypeCVSSv4 && s.Vector == "") {
+			s.Vector = sev.Score
+		}
+	}
+	switch {
+	case e.Score != nil:
+		if *e.Score < 0 || *e.Score > 10 {
+			return s, fmt.Errorf("invalid score: %.1f (must be between 0 and 10)", *e.Score)
+		}
+		s.Score = *e.Score
+	case strings.HasPrefix(s.Vector, "CVSS:3."):
+		score, err := ScoreV3(s.Vector)
+		if err != nil {
+			return s, err
+		}
+		s.Score = score
+	default:
+		return s, errors.New("a score is required when there is no CVSS v3 vector")
+	}
+	return s, nil
+}
+
+// Lookup returns the severity of the first of the given IDs or aliases which is in the data
+func (d Data) Lookup(ids ...string) (Severity, bool) {
+	for _, id := range ids {
+		if s, found := d[id]; found {
+			return s, true
+		}
+	}
+	return Severity{}, false
+}
+
+// ParseMinimum parses a minimum severity, specified as a rating (`low`, `medium`, `high` or `critical`)
+// or as a score between 0 and 10
+func ParseMinimum(value string) (float64, error) {
+	switch strings.ToLower(value) {
+	case "low":
+		return 0.1, nil
+	case "medium":
+		return 4, nil
+	case "high":
+		return 7, nil
+	case "critical":
+		return 9, nil
+	}
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil || score < 0 || score > 10 {
+		return 0, fmt.Errorf("invalid minimum severity: '%s' (must be 'low', 'medium', 'high', 'critical' or a score between 0 and 10)", value)
+	}
+	return score, nil
+}
+
+// weights of the metrics of the CVSS v3 base score
+// see https://www.first.org/cvss/v3.1/specification-document#7-4-Metric-Values
+var weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// ScoreV3 computes the base score of a CVSS v3.x vector
+// see https://www.first.org/cvss/v3.1/specification-document#7-1-Base-Metrics-Equations
+func ScoreV3(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, fmt.Errorf("invalid CVSS v3 vector: '%s'", vector)
+	}
+	metrics := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		name, value, found := strings.Cut(p, ":")
+		if !found {
+			return 0, fmt.Errorf("invalid CVSS v3 vector: '%s'", vector)
+		}
+		metrics[name] = value
+	}
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, fmt.Errorf("invalid CVSS v3 vector: '%s' (missing or invalid scope)", vector)
+	}
+	values := make(map[string]float64, len(weights))
+	for name, w := range weights {
+		value, found := w[metrics[name]]
+		if !found {
+			return 0, fmt.Errorf("invalid CVSS v3 vector: '%s' (missing or invalid '%s' metric)", vector, name)
+		}
+		values[name] = value
+	}
+	if scope == "C" {
+		// the privileges required are more significant when the scope is changed
+		switch metrics["PR"] {
+		case "L":
+			values["PR"] = 0.68
+		case "H":
+			values["PR"] = 0.5
+		}
+	}
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if scope == "C" {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp returns the smallest number with a single decimal which is equal to or higher than the given one,
+// while avoiding floating point errors
+// see https://www.first.org/cvss/v3.1/specification-document#Appendix-A---Floating-Point-Rounding
+func roundUp(value float64) float64 {
+	i := int64(math.Round(value * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
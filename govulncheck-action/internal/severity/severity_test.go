@@ -0,0 +1,148 @@
+package severity_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/severity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreV3(t *testing.T) {
+	tests := []struct {
+		vector   string
+		expected float64
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", expected: 7.5},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", expected: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", expected: 10},
+		{vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", expected: 5.9},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", expected: 5.4},
+		{vector: "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", expected: 1.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.vector, func(t *testing.T) {
+			// when
+			score, err := severity.ScoreV3(test.vector)
+			// then
+			require.NoError(t, err)
+			assert.InDelta(t, test.expected, score, 0.0001)
+		})
+	}
+
+	t.Run("invalid vectors", func(t *testing.T) {
+		for _, vector := range []string{
+			"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H",
+			"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			"CVSS:3.1/AV",
+		} {
+			_, err := severity.ScoreV3(vector)
+			assert.ErrorContains(t, err, "invalid CVSS v3 vector", vector)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	write := func(t *testing.T, contents string) string {
+		path := filepath.Join(t.TempDir(), "severity.json")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+
+	t.Run("valid data", func(t *testing.T) {
+		// given
+		path := write(t, `{
+  "CVE-2025-22871": {"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N"}]},
+  "GHSA-v778-237x-gjrc": {
+    "severity": [
+      {"type": "CVSS_V4", "score": "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N"},
+      {"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"}
+    ],
+    "score": 8.2
+  },
+  "CVE-2025-1767": {"score": 6.5}
+}`)
+		// when
+		data, err := severity.Load(path)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, severity.Data{
+			"CVE-2025-22871": {Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", Score: 7.5},
+			// the CVSS v3 vector is preferred, but the given score is kept
+			"GHSA-v778-237x-gjrc": {Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", Score: 8.2},
+			"CVE-2025-1767":       {Score: 6.5},
+		}, data)
+
+		s, found := data.Lookup("GO-2025-3563", "CVE-2025-22871")
+		require.True(t, found)
+		assert.Equal(t, "7.5 (High) CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", s.String())
+		_, found = data.Lookup("GO-2025-3547")
+		assert.False(t, found)
+	})
+
+	t.Run("CVSS v4 vector without score", func(t *testing.T) {
+		// given
+		path := write(t, `{"CVE-2025-0001": {"severity": [{"type": "CVSS_V4", "score": "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N"}]}}`)
+		// when
+		_, err := severity.Load(path)
+		// then
+		require.EqualError(t, err, "invalid severity data for 'CVE-2025-0001': a score is required when there is no CVSS v3 vector")
+	})
+
+	t.Run("invalid score", func(t *testing.T) {
+		// given
+		path := write(t, `{"CVE-2025-0001": {"score": 11}}`)
+		// when
+		_, err := severity.Load(path)
+		// then
+		require.EqualError(t, err, "invalid severity data for 'CVE-2025-0001': invalid score: 11.0 (must be between 0 and 10)")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		// when
+		_, err := severity.Load(filepath.Join(t.TempDir(), "missing.json"))
+		// then
+		require.ErrorContains(t, err, "failed to read severity data")
+	})
+}
+
+func TestRating(t *testing.T) {
+	for score, expected := range map[float64]string{
+		0:   "None",
+		0.1: "Low",
+		3.9: "Low",
+		4:   "Medium",
+		6.9: "Medium",
+		7:   "High",
+		8.9: "High",
+		9:   "Critical",
+		10:  "Critical",
+	} {
+		assert.Equal(t, expected, severity.Severity{Score: score}.Rating(), score)
+	}
+}
+
+func TestParseMinimum(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"low":      0.1,
+		"Medium":   4,
+		"HIGH":     7,
+		"critical": 9,
+		"5.5":      5.5,
+		"0":        0,
+	} {
+		min, err := severity.ParseMinimum(value)
+		require.NoError(t, err, value)
+		assert.InDelta(t, expected, min, 0.0001, value)
+	}
+	for _, value := range []string{"severe", "11", "-1"} {
+		_, err := severity.ParseMinimum(value)
+		assert.ErrorContains(t, err, "invalid minimum severity", value)
+	}
+}
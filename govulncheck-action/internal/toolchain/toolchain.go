@@ -0,0 +1,118 @@
+package toolchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Auto is the toolchain selection when the scanned path does not specify any Go version
+const Auto = "auto"
+
+// Select returns the value of the `GOTOOLCHAIN` environment variable to use when scanning the given path,
+// so that the code is analyzed against the version of the Go standard library that the project actually uses,
+// and not the version of the Go toolchain installed on the host (or in the container).
+// The `toolchain` directive takes precedence over the `go` directive, and the `go.work` file (if any)
+// takes precedence over the `go.mod` file, as with the `go` command.
+// See https://go.dev/doc/toolchain
+func Select(path string) (string, error) {
+	toolchain, version, err := readWork(filepath.Join(path, "go.work"))
+	if errors.Is(err, os.ErrNotExist) {
+		toolchain, version, err = readMod(filepath.Join(path, "go.mod"))
+	}
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return Auto, nil
+	case err != nil:
+		return "", err
+	case toolchain != "":
+		return toolchain, nil
+	case version != "":
+		return "go" + version, nil
+	default:
+		return Auto, nil
+	}
+}
+
+// readWork returns the `toolchain` and `go` directives of the given `go.work` file
+func readWork(path string) (string, string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	f, err := modfile.ParseWork(path, contents, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse `go.work` file: %w", err)
+	}
+	return toolchainName(f.Toolchain), goVersion(f.Go), nil
+}
+
+// readMod returns the `toolchain` and `go` directives of the given `go.mod` file
+func readMod(path string) (string, string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	f, err := modfile.Parse(path, contents, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse `go.mod` file: %w", err)
+	}
+	return toolchainName(f.Toolchain), goVersion(f.Go), nil
+}
+
+func toolchainName(t *modfile.Toolchain) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+func goVersion(g *modfile.Go) string {
+	if g == nil {
+		return ""
+	}
+	return g.Version
+}
+
+// Verify checks that the dependencies of the module (or workspace) in the given directory have not been modified
+// since they were downloaded
+func Verify(ctx context.Context, dir string, env []string) error {
+	if _, err := run(ctx, dir, env, "mod", "verify"); err != nil {
+		return fmt.Errorf("failed to verify dependencies: %w", err)
+	}
+	return nil
+}
+
+// Version returns the version of the Go toolchain used in the given directory with the given environment
+// (which may differ from the Go version of this binary, depending on the `GOTOOLCHAIN` setting)
+func Version(ctx context.Context, dir string, env []string) (string, error) {
+	output, err := run(ctx, dir, env, "env", "GOVERSION")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the Go version: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// run runs the `go` command with the given arguments, and returns its output
+func run(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	c := exec.CommandContext(ctx, "go", args...)
+	c.Dir = dir
+	c.Env = append(os.Environ(), env...)
+	stderr := &bytes.Buffer{}
+	c.Stderr = stderr
+	output, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
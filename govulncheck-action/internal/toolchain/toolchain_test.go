@@ -0,0 +1,86 @@
+package toolchain_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/toolchain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+
+	testCases := map[string]struct {
+		gowork   string
+		gomod    string
+		expected string
+	}{
+		"toolchain directive": {
+			gomod:    "module example.com/operator\n\ngo 1.25.0\n\ntoolchain go1.25.7\n",
+			expected: "go1.25.7",
+		},
+		"go directive": {
+			gomod:    "module example.com/operator\n\ngo 1.25.0\n",
+			expected: "go1.25.0",
+		},
+		"no directive": {
+			gomod:    "module example.com/operator\n",
+			expected: toolchain.Auto,
+		},
+		"no go.mod file": {
+			expected: toolchain.Auto,
+		},
+		"go.work file takes precedence": {
+			gowork:   "go 1.26.0\n\ntoolchain go1.26.1\n\nuse ./operator\n",
+			gomod:    "module example.com/operator\n\ngo 1.25.0\n\ntoolchain go1.25.7\n",
+			expected: "go1.26.1",
+		},
+		"go directive in go.work file": {
+			gowork:   "go 1.26.0\n\nuse ./operator\n",
+			gomod:    "module example.com/operator\n\ngo 1.25.0\n\ntoolchain go1.25.7\n",
+			expected: "go1.26.0",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// given
+			path := t.TempDir()
+			if tc.gowork != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(path, "go.work"), []byte(tc.gowork), 0o600))
+			}
+			if tc.gomod != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(path, "go.mod"), []byte(tc.gomod), 0o600))
+			}
+			// when
+			gotoolchain, err := toolchain.Select(path)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, gotoolchain)
+		})
+	}
+
+	t.Run("invalid go.mod file", func(t *testing.T) {
+		// given
+		path := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(path, "go.mod"), []byte("module example.com/operator\n\ngo 1.25.0 invalid\n"), 0o600))
+		// when
+		_, err := toolchain.Select(path)
+		// then
+		require.ErrorContains(t, err, "failed to parse `go.mod` file")
+	})
+}
+
+func TestVersion(t *testing.T) {
+	// given
+	path := t.TempDir()
+	// when
+	version, err := toolchain.Version(context.Background(), path, []string{"GOTOOLCHAIN=local"})
+	// then
+	require.NoError(t, err)
+	assert.Regexp(t, `^go1\.`, version)
+}
@@ -0,0 +1,187 @@
+package vulndb
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultSource is the URL of the Go vulnerability database
+const DefaultSource = "https://vuln.go.dev"
+
+// URL returns the URL of the vulnerability database to use with govulncheck.
+// The database can be specified as an `http(s)://` or `file://` URL, or as a path to a local directory
+// (which is converted into a `file://` URL with an absolute path, as expected by govulncheck).
+// A `file://` URL with a relative path (eg: `file://./db`) is also converted into a URL with an absolute path.
+func URL(db string) (string, error) {
+	path := db
+	if u, err := url.Parse(db); err == nil {
+		switch u.Scheme {
+		case "http", "https":
+			return db, nil
+		case "file":
+			if u.Opaque == "" && (u.Host == "" || u.Host == "localhost") {
+				return db, nil
+			}
+			// the relative path is parsed as an opaque value (`file:db`) or as a host and a path (`file://./db`)
+			path = u.Opaque + u.Host + u.Path
+		}
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path of the vulnerability database '%s': %w", db, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid vulnerability database '%s': %w", db, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("vulnerability database '%s' is not a directory", db)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String(), nil
+}
+
+// LastModified returns the time when the vulnerability database was last modified,
+// as specified in its `index/db` endpoint
+func LastModified(ctx context.Context, client *http.Client, db string) (time.Time, error) {
+	u, err := url.Parse(db)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid vulnerability database URL '%s': %w", db, err)
+	}
+	var data []byte
+	switch u.Scheme {
+	case "file":
+		path := filepath.Join(filepath.FromSlash(u.Path), "index", "db.json")
+		if data, err = os.ReadFile(path); err != nil {
+			return time.Time{}, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+	default:
+		if data, err = get(ctx, client, strings.TrimRight(db, "/"), "index/db"); err != nil {
+			return time.Time{}, err
+		}
+	}
+	meta := struct {
+		Modified time.Time `json:"modified"`
+	}{}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return time.Time{}, fmt.Errorf("failed to unmarshal the metadata of the vulnerability database: %w", err)
+	}
+	return meta.Modified, nil
+}
+
+// moduleMeta is an entry of the `index/modules` endpoint of the vulnerability database
+// see https://go.dev/security/vuln/database#api
+type moduleMeta struct {
+	Path  string `json:"path"`
+	Vulns []struct {
+		ID string `json:"id"`
+	} `json:"vulns"`
+}
+
+// indexEndpoints are the endpoints of the index of the vulnerability database
+var indexEndpoints = []string{"index/db", "index/modules", "index/vulns"}
+
+// Snapshot downloads the vulnerability database from the given source into the given directory,
+// which can then be used by govulncheck with a `file://` URL.
+// The entries are downloaded before the index, so that an interrupted snapshot cannot be used as a valid database.
+func Snapshot(ctx context.Context, logger *slog.Logger, client *http.Client, source, dir string, parallelism int) error {
+	source = strings.TrimRight(source, "/")
+	index := make(map[string][]byte, len(indexEndpoints))
+	for _, endpoint := range indexEndpoints {
+		data, err := get(ctx, client, source, endpoint)
+		if err != nil {
+			return err
+		}
+		index[endpoint] = data
+	}
+	var modules []moduleMeta
+	if err := json.Unmarshal(index["index/modules"], &modules); err != nil {
+		return fmt.Errorf("failed to unmarshal modules index: %w", err)
+	}
+	ids := make(map[string]bool)
+	for _, m := range modules {
+		for _, v := range m.Vulns {
+			if v.ID == "" || strings.ContainsAny(v.ID, `/\.`) {
+				return fmt.Errorf("invalid vulnerability ID in modules index: '%s'", v.ID)
+			}
+			ids[v.ID] = true
+		}
+	}
+	logger.Info("downloading vulnerability database", "source", source, "entries", len(ids), "dir", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "ID"), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(parallelism, 1))
+	for id := range ids {
+		g.Go(func() error {
+			endpoint := "ID/" + id
+			data, err := get(gctx, client, source, endpoint)
+			if err != nil {
+				return err
+			}
+			logger.Debug("downloaded vulnerability", "id", id)
+			return write(dir, endpoint, data)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "index"), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	for _, endpoint := range indexEndpoints {
+		if err := write(dir, endpoint, index[endpoint]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get returns the decompressed content of the given endpoint
+// (the vulnerability database serves gzipped JSON files over HTTP)
+func get(ctx context.Context, client *http.Client, source, endpoint string) ([]byte, error) {
+	u := fmt.Sprintf("%s/%s.json.gz", source, endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for '%s': %w", u, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s': %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get '%s': unexpected status '%s'", u, resp.Status)
+	}
+	r, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress '%s': %w", u, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", u, err)
+	}
+	return data, nil
+}
+
+// write writes the data of the given endpoint in the directory, as expected by govulncheck for `file://` databases
+func write(dir, endpoint string, data []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(endpoint)+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	return nil
+}
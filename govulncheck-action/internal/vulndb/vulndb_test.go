@@ -0,0 +1,206 @@
+package vulndb_test
+
+import (
+	"compress/gzip"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-cicd/govulncheck-action/internal/vulndb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL(t *testing.T) {
+
+	t.Run("http URL", func(t *testing.T) {
+		// when
+		u, err := vulndb.URL("https://vuln.go.dev")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://vuln.go.dev", u)
+	})
+
+	t.Run("file URL", func(t *testing.T) {
+		// when
+		u, err := vulndb.URL("file:///tmp/vulndb")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "file:///tmp/vulndb", u)
+	})
+
+	t.Run("file URL with a relative path", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "db"), 0o755))
+		t.Chdir(dir)
+		for _, db := range []string{"file://./db", "file:db"} {
+			// when
+			u, err := vulndb.URL(db)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(dir, "db")), u)
+		}
+	})
+
+	t.Run("file URL with a missing relative path", func(t *testing.T) {
+		// given
+		t.Chdir(t.TempDir())
+		// when
+		_, err := vulndb.URL("file://./missing")
+		// then
+		require.ErrorContains(t, err, "invalid vulnerability database 'file://./missing'")
+	})
+
+	t.Run("local directory", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		// when
+		u, err := vulndb.URL(dir)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "file://"+filepath.ToSlash(dir), u)
+	})
+
+	t.Run("missing local directory", func(t *testing.T) {
+		// when
+		_, err := vulndb.URL(filepath.Join(t.TempDir(), "missing"))
+		// then
+		require.ErrorContains(t, err, "invalid vulnerability database")
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	endpoints := map[string]string{
+		"/index/db.json.gz":              `{"modified":"2025-04-24T18:14:57Z"}`,
+		"/index/modules.json.gz":         `[{"path":"stdlib","vulns":[{"id":"GO-2025-3563"}]},{"path":"k8s.io/kubernetes","vulns":[{"id":"GO-2025-3547"}]}]`,
+		"/index/vulns.json.gz":           `[{"id":"GO-2025-3547"},{"id":"GO-2025-3563"}]`,
+		"/ID/GO-2025-3563.json.gz":       `{"id":"GO-2025-3563"}`,
+		"/ID/GO-2025-3547.json.gz":       `{"id":"GO-2025-3547"}`,
+		"/index/invalid-modules.json.gz": `[{"path":"stdlib","vulns":[{"id":"../GO-2025-3563"}]}]`,
+	}
+	newServer := func(t *testing.T, overrides map[string]string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if o, found := overrides[path]; found {
+				path = o
+			}
+			data, found := endpoints[path]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			gw := gzip.NewWriter(w)
+			_, err := gw.Write([]byte(data))
+			require.NoError(t, err)
+			require.NoError(t, gw.Close())
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// given
+		srv := newServer(t, nil)
+		dir := t.TempDir()
+		// when
+		err := vulndb.Snapshot(context.Background(), logger, srv.Client(), srv.URL+"/", dir, 2)
+		// then
+		require.NoError(t, err)
+		for _, f := range []string{"index/db.json", "index/modules.json", "index/vulns.json", "ID/GO-2025-3563.json", "ID/GO-2025-3547.json"} {
+			actual, err := os.ReadFile(filepath.Join(dir, f))
+			require.NoError(t, err)
+			assert.Equal(t, endpoints["/"+f+".gz"], string(actual))
+		}
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		// given
+		srv := newServer(t, map[string]string{
+			"/ID/GO-2025-3547.json.gz": "/ID/GO-0000-missing.json.gz",
+		})
+		dir := t.TempDir()
+		// when
+		err := vulndb.Snapshot(context.Background(), logger, srv.Client(), srv.URL, dir, 1)
+		// then
+		require.ErrorContains(t, err, "GO-2025-3547.json.gz': unexpected status '404 Not Found'")
+		// index is not written
+		_, err = os.Stat(filepath.Join(dir, "index", "modules.json"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("invalid vulnerability ID", func(t *testing.T) {
+		// given
+		srv := newServer(t, map[string]string{
+			"/index/modules.json.gz": "/index/invalid-modules.json.gz",
+		})
+		// when
+		err := vulndb.Snapshot(context.Background(), logger, srv.Client(), srv.URL, t.TempDir(), 1)
+		// then
+		require.EqualError(t, err, "invalid vulnerability ID in modules index: '../GO-2025-3563'")
+	})
+
+	t.Run("not gzipped", func(t *testing.T) {
+		// given
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+		// when
+		err := vulndb.Snapshot(context.Background(), logger, srv.Client(), srv.URL, t.TempDir(), 1)
+		// then
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "failed to decompress"), err.Error())
+	})
+}
+
+func TestLastModified(t *testing.T) {
+
+	t.Run("http database", func(t *testing.T) {
+		// given
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/index/db.json.gz" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			gw := gzip.NewWriter(w)
+			_, err := gw.Write([]byte(`{"modified":"2025-04-24T18:14:57Z"}`))
+			require.NoError(t, err)
+			require.NoError(t, gw.Close())
+		}))
+		defer srv.Close()
+		// when
+		modified, err := vulndb.LastModified(context.Background(), srv.Client(), srv.URL)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 4, 24, 18, 14, 57, 0, time.UTC), modified)
+	})
+
+	t.Run("local database", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "index"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index", "db.json"), []byte(`{"modified":"2025-04-24T18:14:57Z"}`), 0o600))
+		db, err := vulndb.URL(dir)
+		require.NoError(t, err)
+		// when
+		modified, err := vulndb.LastModified(context.Background(), http.DefaultClient, db)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 4, 24, 18, 14, 57, 0, time.UTC), modified)
+	})
+
+	t.Run("missing local database", func(t *testing.T) {
+		// when
+		_, err := vulndb.LastModified(context.Background(), http.DefaultClient, "file://"+filepath.ToSlash(t.TempDir()))
+		// then
+		require.ErrorContains(t, err, "failed to read")
+	})
+}